@@ -0,0 +1,113 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/tokenizer"
+)
+
+// Budgeting strategy for LLM I/O, safe for concurrent use. It aborts
+// the execution once the money budget is spent. The cost is estimated from
+// the reply usage and the price of the model. Cached replies
+// ([chatter.Reply.Cached]) are not charged, place the budget in front of
+// the cache ([Cache], [SemanticCache]).
+//
+//	price, _ := pricing.Lookup("anthropic.claude-3-5-haiku")
+//	llm := aio.NewBudget(5.0, price, llm)
+type Budget struct {
+	chatter.Chatter
+	mu        sync.Mutex
+	price     chatter.Price
+	tokenizer chatter.Tokenizer
+	maxCost   float64
+	cost      float64
+	reserved  float64
+}
+
+var (
+	_ chatter.Chatter = (*Budget)(nil)
+	_ chatter.Wrapper = (*Budget)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (b *Budget) Unwrap() chatter.Chatter { return b.Chatter }
+
+// Creates budgeting strategy, the budget is defined in USD.
+func NewBudget(maxCost float64, price chatter.Price, chatter chatter.Chatter) *Budget {
+	return &Budget{
+		Chatter:   chatter,
+		price:     price,
+		maxCost:   maxCost,
+		tokenizer: tokenizer.ForModel(""),
+	}
+}
+
+// Use tokenizer to estimate prompt size, heuristic is used by default.
+func (b *Budget) WithTokenizer(tokenizer chatter.Tokenizer) *Budget {
+	b.tokenizer = tokenizer
+	return b
+}
+
+// Cost returns the money spent in USD.
+func (b *Budget) Cost() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.cost
+}
+
+func (b *Budget) ResetBudget() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.cost = 0
+}
+
+func (b *Budget) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	reserved := b.price.Cost(estimate(b.tokenizer, prompt, opts))
+	if err := b.acquire(reserved); err != nil {
+		return nil, err
+	}
+
+	reply, err := b.Chatter.Prompt(ctx, prompt, opts...)
+
+	b.mu.Lock()
+	b.reserved -= reserved
+	if err == nil && !reply.Cached {
+		b.cost += b.price.Cost(reply.Usage)
+	}
+	b.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// checks the budget and reserves the cost
+func (b *Budget) acquire(reserve float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.maxCost > 0 {
+		if cost := b.cost + b.reserved; cost >= b.maxCost {
+			return fmt.Errorf("execution aborted, $%.4f is exceeded the budget", cost)
+		}
+	}
+
+	b.reserved += reserve
+
+	return nil
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+func TestBudget(t *testing.T) {
+	llm := mock{&chatter.Reply{
+		Stage:   chatter.LLM_RETURN,
+		Usage:   chatter.Usage{InputTokens: 1000000, ReplyTokens: 100000},
+		Content: []chatter.Content{chatter.Text("ok")},
+	}}
+	price := chatter.Price{Input: 1.0, Reply: 10.0}
+
+	b := aio.NewBudget(5.0, price, llm)
+
+	for i := 0; i < 3; i++ {
+		_, err := b.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+		it.Then(t).Should(it.Nil(err))
+	}
+
+	_, err := b.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+	it.Then(t).Should(
+		it.Fail(func() error { return err }),
+		it.Equal(b.Cost(), 6.0),
+	)
+
+	b.ResetBudget()
+	_, err = b.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(b.Cost(), 2.0),
+	)
+}
+
+func TestBudgetCacheHits(t *testing.T) {
+	llm := mock{&chatter.Reply{
+		Stage:   chatter.LLM_RETURN,
+		Usage:   chatter.Usage{InputTokens: 1000000, ReplyTokens: 100000},
+		Content: []chatter.Content{chatter.Text("ok")},
+	}}
+	price := chatter.Price{Input: 1.0, Reply: 10.0}
+
+	b := aio.NewBudget(5.0, price, aio.NewCache(keyval{}, llm))
+
+	for i := 0; i < 3; i++ {
+		reply, err := b.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "ok"),
+		)
+	}
+
+	it.Then(t).Should(
+		it.Equal(b.Cost(), 2.0),
+	)
+}
+
+func TestBudgetConcurrent(t *testing.T) {
+	llm := gated{
+		release: make(chan struct{}),
+		reply:   &chatter.Reply{Usage: chatter.Usage{ReplyTokens: 100000}},
+	}
+	price := chatter.Price{Reply: 10.0}
+
+	b := aio.NewBudget(2.0, price, llm).WithTokenizer(words{})
+
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")}, chatter.MaxTokens(100000))
+			if err != nil {
+				failed.Add(1)
+			}
+		}()
+	}
+
+	// reservations of 2 requests exhaust the budget, others fail immediately
+	for failed.Load() < 6 {
+		runtime.Gosched()
+	}
+	close(llm.release)
+	wg.Wait()
+
+	it.Then(t).Should(
+		it.Equal(failed.Load(), 6),
+		it.Equal(b.Cost(), 2.0),
+	)
+}
@@ -9,12 +9,15 @@
 package aio
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha1"
-	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"hash"
 	"log/slog"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/kshard/chatter"
 )
@@ -34,38 +37,145 @@ type KeyVal interface {
 	Eraser
 }
 
+// identity of the model behind the client, the package chatter is shadowed
+// by arguments of constructors.
+func modelID(llm chatter.Chatter) string { return chatter.ModelID(llm) }
+
 // Caching strategy for LLMs I/O
+//
+// The cache key is derived from the whole request: the conversation,
+// options and the model identity. The identity is obtained from the client
+// (see [chatter.ModelID]), use WithModel if the client does not report it,
+// otherwise models sharing the storage collide. Optional salt namespaces
+// the entries, changing salt (e.g. prompt version) invalidates old entries.
+//
+// Final replies and tool invocations are cached as versioned entries with
+// the original usage, model identity and creation time. Entries expire after
+// optional TTL, expired entries are treated as misses. Cache hits do not
+// consume the model, they are marked as [chatter.Reply.Cached] and report
+// the usage of the original reply.
 type Cache struct {
 	chatter.Chatter
 	cache KeyVal
+	model string
+	salt  string
+	ttl   time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
 }
 
-var _ chatter.Chatter = (*Cache)(nil)
+var (
+	_ chatter.Chatter = (*Cache)(nil)
+	_ chatter.Wrapper = (*Cache)(nil)
+)
 
-func init() {
-	gob.Register(chatter.Text(""))
-	gob.Register(chatter.Vector{})
-	gob.Register([]chatter.Content{})
+// Unwrap returns the wrapped chatter
+func (c *Cache) Unwrap() chatter.Chatter { return c.Chatter }
+
+// Cache statistics
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
 }
 
 // Creates read-through caching layer for LLM client.
 //
-// Use github.com/akrylysov/pogreb to cache chatter on local file systems:
+// Use github.com/kshard/chatter/aio/keyval for built-in storages:
 //
 //	llm, err := /* create LLM client */
-//	db, err := pogreb.Open("llm.cache", nil)
+//	db, err := keyval.NewDir("llm.cache")
 //	text := aio.NewCache(db, llm)
+//
+// Any other key-value storage is usable (e.g. github.com/akrylysov/pogreb).
 func NewCache(cache KeyVal, chatter chatter.Chatter) *Cache {
 	return &Cache{
 		Chatter: chatter,
+		model:   modelID(chatter),
 		cache:   cache,
 	}
 }
 
-func (c *Cache) HashKey(prompt string) []byte {
+// Set the model identity, it is a part of the cache key.
+func (c *Cache) WithModel(model string) *Cache {
+	c.model = model
+	return c
+}
+
+// Set the salt (e.g. namespace or version), it is a part of the cache key.
+func (c *Cache) WithSalt(salt string) *Cache {
+	c.salt = salt
+	return c
+}
+
+// Set time-to-live of cache entries, entries never expire by default.
+func (c *Cache) WithTTL(ttl time.Duration) *Cache {
+	c.ttl = ttl
+	return c
+}
+
+// Stats returns hit/miss statistics of the cache.
+func (c *Cache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// HashKey derives the cache key from canonical encoding of the request.
+// The options are order-insensitive.
+func (c *Cache) HashKey(prompt []chatter.Message, opts ...chatter.Opt) ([]byte, error) {
+	return hashKey(c.salt, c.model, prompt, opts)
+}
+
+// canonical hash of the request
+func hashKey(salt, model string, prompt []chatter.Message, opts []chatter.Opt) ([]byte, error) {
+	seq, err := chatter.MarshalMessages(canonical(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]string, len(opts))
+	for i, opt := range opts {
+		val, err := json.Marshal(opt)
+		if err != nil {
+			return nil, err
+		}
+		options[i] = fmt.Sprintf("%T=%s", opt, val)
+	}
+	sort.Strings(options)
+
 	hash := sha1.New()
-	hash.Write([]byte(prompt))
-	return hash.Sum(nil)
+	writeField(hash, []byte(salt))
+	writeField(hash, []byte(model))
+	fmt.Fprintf(hash, "%d:", len(options))
+	for _, opt := range options {
+		writeField(hash, []byte(opt))
+	}
+	writeField(hash, seq)
+
+	return hash.Sum(nil), nil
+}
+
+// canonical conversation keeps only the role and content of messages,
+// the usage and stage of replies do not influence the request.
+func canonical(prompt []chatter.Message) []chatter.Message {
+	seq := make([]chatter.Message, len(prompt))
+	for i, msg := range prompt {
+		switch v := msg.(type) {
+		case *chatter.Reply:
+			seq[i] = &chatter.Reply{Content: v.Content}
+		default:
+			seq[i] = msg
+		}
+	}
+	return seq
+}
+
+// writes length-prefixed field, so that fields boundaries are unambiguous.
+func writeField(h hash.Hash, b []byte) {
+	fmt.Fprintf(h, "%d:", len(b))
+	h.Write(b)
 }
 
 func (c *Cache) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
@@ -73,71 +183,107 @@ func (c *Cache) Prompt(ctx context.Context, prompt []chatter.Message, opts ...ch
 		return nil, fmt.Errorf("bad request, empty prompt")
 	}
 
-	hkey := c.HashKey(prompt[len(prompt)-1].String())
+	hkey, err := c.HashKey(prompt, opts...)
+	if err != nil {
+		slog.Warn("failed to derive cache key, bypass cache", "err", err)
+		return c.Chatter.Prompt(ctx, prompt, opts...)
+	}
+
 	val, err := c.cache.Get(hkey)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(val) != 0 {
-		reply, err := decode(val)
-		if err == nil {
+		reply, err := c.decode(val)
+		switch {
+		case err != nil:
+			slog.Warn("failed to decode cached LLM reply", "err", err)
+			c.cache.Delete(hkey)
+		case reply == nil:
+			c.cache.Delete(hkey)
+		default:
+			c.hits.Add(1)
+			reply.Cached = true
 			return reply, nil
 		}
-
-		slog.Warn("failed to decode cached LLM reply", "err", err)
-		c.cache.Delete(hkey)
 	}
 
+	c.misses.Add(1)
+
 	reply, err := c.Chatter.Prompt(ctx, prompt, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	if reply.Stage == chatter.LLM_RETURN {
-		bin, err := encode(reply)
-		switch {
-		case err != nil:
+	if reply.Stage == chatter.LLM_RETURN || reply.Stage == chatter.LLM_INVOKE {
+		bin, err := c.encode(reply)
+		if err != nil {
 			slog.Warn("failed to encode LLM reply", "err", err)
 			return reply, nil
-		case bin == nil:
-			return reply, nil
-		default:
-			err = c.cache.Put(hkey, bin)
-			if err != nil {
-				slog.Warn("failed to cache LLM reply", "err", err)
-			}
-			return reply, nil
+		}
+
+		if err := c.cache.Put(hkey, bin); err != nil {
+			slog.Warn("failed to cache LLM reply", "err", err)
 		}
 	}
 
 	return reply, nil
 }
 
-func encode(reply *chatter.Reply) ([]byte, error) {
-	if reply == nil {
-		return nil, nil
-	}
+//------------------------------------------------------------------------------
+
+// Version of cache entries format
+const cacheVersion = 1
+
+// Cache entry
+type cacheEntry struct {
+	Version int             `json:"version"`
+	Model   string          `json:"model,omitempty"`
+	Created time.Time       `json:"created"`
+	TTL     time.Duration   `json:"ttl,omitempty"`
+	Reply   json.RawMessage `json:"reply"`
+}
 
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	if err := enc.Encode(&reply.Content); err != nil {
+func (c *Cache) encode(reply *chatter.Reply) ([]byte, error) {
+	msg, err := chatter.MarshalMessage(reply)
+	if err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return json.Marshal(cacheEntry{
+		Version: cacheVersion,
+		Model:   c.model,
+		Created: time.Now().UTC(),
+		TTL:     c.ttl,
+		Reply:   msg,
+	})
 }
 
-func decode(data []byte) (*chatter.Reply, error) {
-	dec := gob.NewDecoder(bytes.NewBuffer(data))
+// decodes cache entry, returns nil if entry is expired.
+func (c *Cache) decode(data []byte) (*chatter.Reply, error) {
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	if entry.Version != cacheVersion {
+		return nil, fmt.Errorf("unsupported cache entry version %d", entry.Version)
+	}
+
+	if entry.TTL > 0 && time.Since(entry.Created) > entry.TTL {
+		return nil, nil
+	}
 
-	var val []chatter.Content
-	if err := dec.Decode(&val); err != nil {
+	msg, err := chatter.UnmarshalMessage(entry.Reply)
+	if err != nil {
 		return nil, err
 	}
 
-	return &chatter.Reply{
-		Stage:   chatter.LLM_RETURN,
-		Content: val,
-	}, nil
+	reply, ok := msg.(*chatter.Reply)
+	if !ok {
+		return nil, fmt.Errorf("unexpected cache entry %T", msg)
+	}
+
+	return reply, nil
 }
@@ -11,7 +11,9 @@ package aio_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/kshard/chatter"
 	"github.com/kshard/chatter/aio"
@@ -51,15 +53,220 @@ func TestCache(t *testing.T) {
 		t.Fatalf("unexpected content[1]: %v", reply.Content[1])
 	}
 
+	hkey, err := c.HashKey(prompt.ToSeq())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	for k := range kv {
-		hkey := c.HashKey(prompt.String())
 		if !bytes.Equal([]byte(k), hkey) {
 			t.Errorf("unexpected key")
 		}
 	}
 }
 
+func TestCacheKey(t *testing.T) {
+	ctx := context.Background()
+	question := chatter.Text("What is the capital of Finland?")
+
+	for name, f := range map[string]func(*aio.Cache) error{
+		"Stratum": func(c *aio.Cache) error {
+			_, err := c.Prompt(ctx, []chatter.Message{chatter.Stratum("Answer in French."), question})
+			return err
+		},
+		"History": func(c *aio.Cache) error {
+			_, err := c.Prompt(ctx, []chatter.Message{chatter.Text("Hello"), &chatter.Reply{Content: []chatter.Content{chatter.Text("Hi")}}, question})
+			return err
+		},
+		"Options": func(c *aio.Cache) error {
+			_, err := c.Prompt(ctx, []chatter.Message{question}, chatter.Temperature(0.9))
+			return err
+		},
+		"Registry": func(c *aio.Cache) error {
+			_, err := c.Prompt(ctx, []chatter.Message{question}, chatter.Registry{{Cmd: "search"}})
+			return err
+		},
+		"Model": func(c *aio.Cache) error {
+			_, err := c.WithModel("other").Prompt(ctx, []chatter.Message{question})
+			return err
+		},
+		"Salt": func(c *aio.Cache) error {
+			_, err := c.WithSalt("v2").Prompt(ctx, []chatter.Message{question})
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Helsinki")}}}
+			c := aio.NewCache(keyval{}, llm).WithModel("model")
+
+			if _, err := c.Prompt(ctx, []chatter.Message{question}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := c.Prompt(ctx, []chatter.Message{question}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if llm.calls != 1 {
+				t.Fatalf("unexpected cache miss")
+			}
+
+			if err := f(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if llm.calls != 2 {
+				t.Fatalf("unexpected cache hit")
+			}
+		})
+	}
+
+	t.Run("ReplyMetadata", func(t *testing.T) {
+		c := aio.NewCache(keyval{}, mock{})
+		reply := func(stage chatter.Stage, usage chatter.Usage) []chatter.Message {
+			return []chatter.Message{
+				chatter.Text("Hello"),
+				&chatter.Reply{Stage: stage, Usage: usage, Content: []chatter.Content{chatter.Text("Hi")}},
+				question,
+			}
+		}
+
+		a, err := c.HashKey(reply(chatter.LLM_RETURN, chatter.Usage{InputTokens: 10}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := c.HashKey(reply(chatter.LLM_INCOMPLETE, chatter.Usage{InputTokens: 20}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("reply usage or stage changes the key")
+		}
+	})
+
+	t.Run("SharedStore", func(t *testing.T) {
+		kv := keyval{}
+		haiku := &model{id: "claude-3-haiku", recorder: &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("haiku")}}}}
+		sonnet := &model{id: "claude-3-sonnet", recorder: &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("sonnet")}}}}
+
+		// the identity is discovered through middlewares
+		a := aio.NewCache(kv, aio.NewLimiter(100, 100000, haiku))
+		b := aio.NewCache(kv, sonnet)
+
+		ra, errA := a.Prompt(ctx, []chatter.Message{question})
+		rb, errB := b.Prompt(ctx, []chatter.Message{question})
+		if errA != nil || errB != nil {
+			t.Fatalf("unexpected error: %v %v", errA, errB)
+		}
+
+		if ra.String() != "haiku" || rb.String() != "sonnet" || sonnet.calls != 1 {
+			t.Errorf("models collide in the shared store")
+		}
+	})
+
+	t.Run("OptionsOrder", func(t *testing.T) {
+		c := aio.NewCache(keyval{}, mock{})
+
+		a, err := c.HashKey([]chatter.Message{question}, chatter.Temperature(0.1), chatter.MaxTokens(10))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := c.HashKey([]chatter.Message{question}, chatter.MaxTokens(10), chatter.Temperature(0.1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("options order changes the key")
+		}
+	})
+}
+
+func TestCacheEntry(t *testing.T) {
+	ctx := context.Background()
+	prompt := []chatter.Message{chatter.Text("Draw the weather in Helsinki.")}
+
+	t.Run("Fidelity", func(t *testing.T) {
+		expect := &chatter.Reply{
+			Stage: chatter.LLM_INVOKE,
+			Usage: chatter.Usage{InputTokens: 10, ReplyTokens: 20},
+			Content: []chatter.Content{
+				chatter.Json{ID: "1", Value: json.RawMessage(`{"city":"Helsinki"}`)},
+				chatter.Binary{Name: "image", Type: "image/png", Data: []byte{1, 2, 3}},
+				chatter.Invoke{Cmd: "weather", Args: chatter.Json{ID: "2", Value: json.RawMessage(`{"city":"Helsinki"}`)}},
+			},
+		}
+		llm := &recorder{reply: expect}
+		c := aio.NewCache(keyval{}, llm)
+
+		c.Prompt(ctx, prompt)
+		reply, err := c.Prompt(ctx, prompt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if llm.calls != 1 {
+			t.Fatalf("unexpected cache miss")
+		}
+		// cache hits do not consume the model, the original usage is reported
+		if reply.Stage != expect.Stage || reply.Usage != expect.Usage || !reply.Cached || len(reply.Content) != 3 {
+			t.Fatalf("unexpected reply: %+v", reply)
+		}
+		if v, ok := reply.Content[1].(chatter.Binary); !ok || !bytes.Equal(v.Data, []byte{1, 2, 3}) {
+			t.Errorf("unexpected content[1]: %v", reply.Content[1])
+		}
+		if v, ok := reply.Content[2].(chatter.Invoke); !ok || v.Cmd != "weather" || v.Args.ID != "2" {
+			t.Errorf("unexpected content[2]: %v", reply.Content[2])
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Sunny")}}}
+		c := aio.NewCache(keyval{}, llm).WithTTL(10 * time.Millisecond)
+
+		c.Prompt(ctx, prompt)
+		c.Prompt(ctx, prompt)
+		if llm.calls != 1 {
+			t.Fatalf("unexpected cache miss")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		c.Prompt(ctx, prompt)
+		if llm.calls != 2 {
+			t.Fatalf("expired entry is used")
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Sunny")}}}
+		c := aio.NewCache(keyval{}, llm)
+
+		c.Prompt(ctx, prompt)
+		c.Prompt(ctx, prompt)
+		c.Prompt(ctx, prompt)
+		if stats := c.Stats(); stats.Hits != 2 || stats.Misses != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Sunny")}}}
+		kv := keyval{}
+		c := aio.NewCache(kv, llm)
+
+		hkey, _ := c.HashKey(prompt)
+		kv[string(hkey)] = []byte("gob")
+
+		reply, err := c.Prompt(ctx, prompt)
+		if err != nil || reply.String() != "Sunny" || llm.calls != 1 {
+			t.Errorf("corrupted entry is not recovered")
+		}
+	})
+}
+
 // mock key-value
+// mock of the model that reports its identity
+type model struct {
+	*recorder
+	id string
+}
+
+func (m *model) ModelID() string { return m.id }
+
 type keyval map[string][]byte
 
 func (kv keyval) Get(key []byte) ([]byte, error) {
@@ -21,6 +21,14 @@ type Embedder struct {
 	chatter.Chatter
 }
 
+var (
+	_ chatter.Chatter = (*Embedder)(nil)
+	_ chatter.Wrapper = (*Embedder)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (api *Embedder) Unwrap() chatter.Chatter { return api.Chatter }
+
 func NewEmbedder(chatter chatter.Chatter) *Embedder {
 	return &Embedder{
 		Chatter: chatter,
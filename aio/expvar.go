@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"expvar"
+	"strings"
+)
+
+// Expvar is metrics sink publishing measures via expvar. Counters are keyed
+// by labels "model/feature/stage", failed calls are counted by "model/feature".
+//
+//	{
+//	  "requests":      {"model/feature/stage": n},
+//	  "errors":        {"model/feature": n},
+//	  "latency_ms":    {"model/feature/stage": sum},
+//	  "input_tokens":  {"model/feature/stage": n},
+//	  "reply_tokens":  {"model/feature/stage": n},
+//	  ...
+//	}
+type Expvar struct {
+	requests         *expvar.Map
+	errors           *expvar.Map
+	latency          *expvar.Map
+	inputTokens      *expvar.Map
+	replyTokens      *expvar.Map
+	cacheReadTokens  *expvar.Map
+	cacheWriteTokens *expvar.Map
+	reasoningTokens  *expvar.Map
+}
+
+var _ MetricsSink = (*Expvar)(nil)
+
+// Creates expvar metrics sink, published under the name.
+// It panics if the name is already published (see expvar.Publish).
+func NewExpvar(name string) *Expvar {
+	root := expvar.NewMap(name)
+
+	sub := func(key string) *expvar.Map {
+		m := new(expvar.Map)
+		root.Set(key, m)
+		return m
+	}
+
+	return &Expvar{
+		requests:         sub("requests"),
+		errors:           sub("errors"),
+		latency:          sub("latency_ms"),
+		inputTokens:      sub("input_tokens"),
+		replyTokens:      sub("reply_tokens"),
+		cacheReadTokens:  sub("cache_read_tokens"),
+		cacheWriteTokens: sub("cache_write_tokens"),
+		reasoningTokens:  sub("reasoning_tokens"),
+	}
+}
+
+func (e *Expvar) Measure(m Measure) {
+	if m.Err != nil {
+		e.errors.Add(strings.Join([]string{m.Model, m.Feature}, "/"), 1)
+		return
+	}
+
+	key := strings.Join([]string{m.Model, m.Feature, string(m.Stage)}, "/")
+	e.requests.Add(key, 1)
+	e.latency.Add(key, m.Latency.Milliseconds())
+	e.inputTokens.Add(key, int64(m.Usage.InputTokens))
+	e.replyTokens.Add(key, int64(m.Usage.ReplyTokens))
+	e.cacheReadTokens.Add(key, int64(m.Usage.CacheReadTokens))
+	e.cacheWriteTokens.Add(key, int64(m.Usage.CacheWriteTokens))
+	e.reasoningTokens.Add(key, int64(m.Usage.ReasoningTokens))
+}
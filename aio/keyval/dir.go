@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package keyval
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kshard/chatter/aio"
+)
+
+// Dir is key-value storage on the local file system, each entry is a file.
+// The file is named by hex encoded SHA-256 of the key, sharded into
+// sub-directories by the leading bytes, so that no single directory grows
+// too large. Names are of fixed length, the file never clashes with
+// the shard directory:
+//
+//	root/ab/cd/abcdef...
+//
+// Writes are atomic, the value is written to temporary file and renamed.
+type Dir struct {
+	root string
+}
+
+var _ aio.KeyVal = (*Dir)(nil)
+
+// Creates key-value storage at the directory, the directory is created if
+// it does not exist.
+func NewDir(root string) (*Dir, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	return &Dir{root: root}, nil
+}
+
+// path of the entry
+func (d *Dir) path(key []byte) string {
+	hash := sha256.Sum256(key)
+	name := hex.EncodeToString(hash[:])
+
+	return filepath.Join(d.root, name[0:2], name[2:4], name)
+}
+
+func (d *Dir) Get(key []byte) ([]byte, error) {
+	val, err := os.ReadFile(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return val, err
+}
+
+func (d *Dir) Put(key []byte, val []byte) error {
+	path := d.path(key)
+	dir := filepath.Dir(path)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	fd, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := fd.Write(val); err != nil {
+		fd.Close()
+		os.Remove(fd.Name())
+		return err
+	}
+
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		os.Remove(fd.Name())
+		return err
+	}
+
+	if err := fd.Close(); err != nil {
+		os.Remove(fd.Name())
+		return err
+	}
+
+	if err := os.Rename(fd.Name(), path); err != nil {
+		os.Remove(fd.Name())
+		return err
+	}
+
+	return nil
+}
+
+func (d *Dir) Delete(key []byte) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
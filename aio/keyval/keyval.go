@@ -0,0 +1,14 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package keyval implements key-value storages for caching LLMs I/O
+// (see aio.KeyVal). The storages are safe for concurrent use. Missing keys
+// are reported as empty value without error.
+//
+//	llm := aio.NewCache(keyval.NewLRU(1000, 0), llm)
+package keyval
@@ -0,0 +1,161 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package keyval_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+	"github.com/kshard/chatter/aio/keyval"
+)
+
+func TestLRU(t *testing.T) {
+	t.Run("KeyVal", func(t *testing.T) {
+		testKeyVal(t, keyval.NewLRU(0, 0))
+	})
+
+	t.Run("MaxEntries", func(t *testing.T) {
+		lru := keyval.NewLRU(2, 0)
+		lru.Put([]byte("a"), []byte("1"))
+		lru.Put([]byte("b"), []byte("2"))
+		lru.Get([]byte("a"))
+		lru.Put([]byte("c"), []byte("3"))
+
+		a, _ := lru.Get([]byte("a"))
+		b, _ := lru.Get([]byte("b"))
+		c, _ := lru.Get([]byte("c"))
+		it.Then(t).Should(
+			it.Equal(lru.Len(), 2),
+			it.Equal(string(a), "1"),
+			it.Equal(len(b), 0),
+			it.Equal(string(c), "3"),
+		)
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		lru := keyval.NewLRU(0, 8)
+		lru.Put([]byte("a"), []byte("123"))
+		lru.Put([]byte("b"), []byte("123"))
+		lru.Put([]byte("c"), []byte("1234567890"))
+
+		a, _ := lru.Get([]byte("a"))
+		c, _ := lru.Get([]byte("c"))
+		it.Then(t).Should(
+			it.Equal(lru.Len(), 2),
+			it.Equal(string(a), "123"),
+			it.Equal(len(c), 0),
+		)
+
+		lru.Put([]byte("d"), []byte("12345"))
+		b, _ := lru.Get([]byte("b"))
+		it.Then(t).Should(
+			it.Equal(lru.Len(), 1),
+			it.Equal(len(b), 0),
+		)
+	})
+}
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	dir, err := keyval.NewDir(root)
+	it.Then(t).Must(it.Nil(err))
+
+	t.Run("KeyVal", func(t *testing.T) {
+		testKeyVal(t, dir)
+	})
+
+	t.Run("Sharded", func(t *testing.T) {
+		err := dir.Put([]byte("key"), []byte("val"))
+		it.Then(t).Must(it.Nil(err))
+
+		// sha256 of the key
+		name := "2c70e12b7a0646f92279f427c7b38e7334d8e5389cff167a1dc30e73f826b683"
+		val, err := os.ReadFile(filepath.Join(root, "2c", "70", name))
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(val), "val"),
+		)
+	})
+
+	t.Run("PrefixKeys", func(t *testing.T) {
+		// keys are prefixes of each other, e.g. hex "ab" and "abab..."
+		keys := [][]byte{{0xab}, {0xab, 0xab}, {0xab, 0xab, 0xcd}, {}}
+		for i, key := range keys {
+			it.Then(t).Must(it.Nil(dir.Put(key, []byte{byte(i)})))
+		}
+
+		for i, key := range keys {
+			val, err := dir.Get(key)
+			it.Then(t).Should(
+				it.Nil(err),
+				it.Seq(val).Equal(byte(i)),
+			)
+		}
+	})
+}
+
+func testKeyVal(t *testing.T, kv aio.KeyVal) {
+	t.Helper()
+
+	val, err := kv.Get([]byte("key"))
+	it.Then(t).Should(it.Nil(err), it.Equal(len(val), 0))
+
+	it.Then(t).Must(it.Nil(kv.Put([]byte("key"), []byte("val"))))
+	val, err = kv.Get([]byte("key"))
+	it.Then(t).Should(it.Nil(err), it.Equal(string(val), "val"))
+
+	it.Then(t).Must(it.Nil(kv.Put([]byte("key"), []byte("new"))))
+	val, err = kv.Get([]byte("key"))
+	it.Then(t).Should(it.Nil(err), it.Equal(string(val), "new"))
+
+	it.Then(t).Must(it.Nil(kv.Delete([]byte("key"))))
+	it.Then(t).Must(it.Nil(kv.Delete([]byte("key"))))
+	val, err = kv.Get([]byte("key"))
+	it.Then(t).Should(it.Nil(err), it.Equal(len(val), 0))
+
+	// concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := []byte(fmt.Sprintf("key-%d", i%4))
+			kv.Put(key, []byte("val"))
+			kv.Get(key)
+		}(i)
+	}
+	wg.Wait()
+
+	// cache
+	llm := aio.NewCache(kv, mock{})
+	reply, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+	it.Then(t).Should(it.Nil(err), it.Equal(reply.String(), "pong"))
+
+	reply, err = llm.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(reply.String(), "pong"),
+		it.Equal(llm.Stats().Hits, 1),
+	)
+}
+
+type mock struct{}
+
+func (mock) Usage() chatter.Usage { return chatter.Usage{} }
+
+func (mock) Prompt(context.Context, []chatter.Message, ...chatter.Opt) (*chatter.Reply, error) {
+	return &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("pong")}}, nil
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package keyval
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/kshard/chatter/aio"
+)
+
+// LRU is in-memory key-value storage, it evicts least recently used entries
+// when the entries or size limits are exceeded.
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	queue      *list.List
+	index      map[string]*list.Element
+}
+
+var _ aio.KeyVal = (*LRU)(nil)
+
+type lruEntry struct {
+	key string
+	val []byte
+}
+
+// Creates in-memory LRU storage limited by number of entries and total size
+// of keys and values in bytes. Zero disables the limit.
+func NewLRU(maxEntries, maxBytes int) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		queue:      list.New(),
+		index:      make(map[string]*list.Element),
+	}
+}
+
+func (lru *LRU) Get(key []byte) ([]byte, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	e, has := lru.index[string(key)]
+	if !has {
+		return nil, nil
+	}
+
+	lru.queue.MoveToFront(e)
+
+	val := e.Value.(*lruEntry).val
+	return append([]byte(nil), val...), nil
+}
+
+func (lru *LRU) Put(key []byte, val []byte) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	lru.remove(string(key))
+
+	size := len(key) + len(val)
+	if lru.maxBytes > 0 && size > lru.maxBytes {
+		// entry never fits the storage
+		return nil
+	}
+
+	entry := &lruEntry{key: string(key), val: append([]byte(nil), val...)}
+	lru.index[entry.key] = lru.queue.PushFront(entry)
+	lru.bytes += size
+
+	for (lru.maxEntries > 0 && lru.queue.Len() > lru.maxEntries) ||
+		(lru.maxBytes > 0 && lru.bytes > lru.maxBytes) {
+		lru.remove(lru.queue.Back().Value.(*lruEntry).key)
+	}
+
+	return nil
+}
+
+func (lru *LRU) Delete(key []byte) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	lru.remove(string(key))
+	return nil
+}
+
+// Len returns number of entries in the storage.
+func (lru *LRU) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return lru.queue.Len()
+}
+
+// remove entry, must be called under the lock
+func (lru *LRU) remove(key string) {
+	e, has := lru.index[key]
+	if !has {
+		return
+	}
+
+	entry := lru.queue.Remove(e).(*lruEntry)
+	delete(lru.index, key)
+	lru.bytes -= len(entry.key) + len(entry.val)
+}
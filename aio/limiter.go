@@ -11,60 +11,198 @@ package aio
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/tokenizer"
 	"golang.org/x/time/rate"
 )
 
-// Rate limit startegy for LLMs I/O
+// Rate limit startegy for LLMs I/O.
+//
+// The limiter is safe for concurrent use. Each request reserves the estimated
+// token cost (prompt size and reply quota) before the call, the reservation
+// is reconciled with actual reply usage afterwards: unused tokens are refunded,
+// overuse is charged to the budget.
 type Limiter struct {
 	chatter.Chatter
-	debt int
-	rps  *rate.Limiter
-	tps  *rate.Limiter
+	rps       *rate.Limiter
+	tps       *bucket
+	tokenizer chatter.Tokenizer
 }
 
-var _ chatter.Chatter = (*Limiter)(nil)
+var (
+	_ chatter.Chatter = (*Limiter)(nil)
+	_ chatter.Wrapper = (*Limiter)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (c *Limiter) Unwrap() chatter.Chatter { return c.Chatter }
 
 // Create rate limit strategy for LLMs.
 // It defines per minute policy for requests and tokens.
 func NewLimiter(requestPerMin int, tokensPerMin int, chatter chatter.Chatter) *Limiter {
 	return &Limiter{
-		Chatter: chatter,
-		debt:    0,
-		rps:     rate.NewLimiter(rate.Limit(requestPerMin)/60, requestPerMin),
-		tps:     rate.NewLimiter(rate.Limit(tokensPerMin)/60, tokensPerMin),
+		Chatter:   chatter,
+		rps:       rate.NewLimiter(rate.Limit(requestPerMin)/60, requestPerMin),
+		tps:       newBucket(float64(tokensPerMin)/60, tokensPerMin),
+		tokenizer: tokenizer.ForModel(""),
 	}
 }
 
+// Use tokenizer to estimate prompt size, heuristic is used by default.
+func (c *Limiter) WithTokenizer(tokenizer chatter.Tokenizer) *Limiter {
+	c.tokenizer = tokenizer
+	return c
+}
+
 func (c *Limiter) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
 	if err := c.rps.Wait(ctx); err != nil {
 		return nil, err
 	}
 
-	if err := c.tps.WaitN(ctx, c.debt); err != nil {
+	reserved := c.estimate(prompt, opts)
+	if err := c.tps.WaitN(ctx, reserved); err != nil {
 		return nil, err
 	}
 
 	reply, err := c.Chatter.Prompt(ctx, prompt, opts...)
 	if err != nil {
+		c.tps.Refund(reserved)
 		return nil, err
 	}
 
-	c.debt = reply.Usage.InputTokens + reply.Usage.ReplyTokens
+	used := reply.Usage.InputTokens + reply.Usage.ReplyTokens
+	switch {
+	case used < reserved:
+		c.tps.Refund(reserved - used)
+	case used > reserved:
+		c.tps.Charge(used - reserved)
+	}
 
 	slog.Debug("LLM is prompted",
 		slog.Float64("budget", c.tps.Tokens()),
-		slog.Int("debt", c.debt),
-		slog.Group("session",
-			slog.Int("inputTokens", c.Chatter.Usage().InputTokens),
-			slog.Int("replyTokens", c.Chatter.Usage().ReplyTokens),
-		),
-		slog.Group("prompt",
-			slog.Int("inputTokens", reply.Usage.InputTokens),
-			slog.Int("replyTokens", reply.Usage.ReplyTokens),
-		),
+		slog.Int("reserved", reserved),
+		slog.Int("used", used),
+		slog.Any("session", c.Chatter.Usage()),
+		slog.Any("prompt", reply.Usage),
 	)
 
 	return reply, nil
 }
+
+// estimates token cost of the request as prompt size and reply quota.
+func (c *Limiter) estimate(prompt []chatter.Message, opts []chatter.Opt) int {
+	n := chatter.CountTokens(c.tokenizer, prompt)
+
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case chatter.MaxTokens:
+			n += int(v)
+		}
+	}
+
+	return n
+}
+
+//------------------------------------------------------------------------------
+
+// Token bucket that permits debt and refunds. Unlike rate.Limiter, consumed
+// tokens are returned to the bucket any time, which is required to reconcile
+// reservation with actual usage after LLM replies.
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	return &bucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// advance refills the bucket, must be called under the lock
+func (b *bucket) advance(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// Reserve n tokens, returns the delay after which the reservation is usable.
+func (b *bucket) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(time.Now())
+	b.tokens -= float64(n)
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// WaitN blocks until n tokens are reserved or context is cancelled.
+// Cancelled reservation is refunded.
+func (b *bucket) WaitN(ctx context.Context, n int) error {
+	if n <= 0 {
+		return ctx.Err()
+	}
+
+	delay := b.reserve(n)
+	if delay == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		b.Refund(n)
+		return context.DeadlineExceeded
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		b.Refund(n)
+		return ctx.Err()
+	}
+}
+
+// Refund returns n tokens back to the bucket.
+func (b *bucket) Refund(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(time.Now())
+	b.tokens = min(b.burst, b.tokens+float64(n))
+}
+
+// Charge takes n tokens from the bucket without waiting, the debt is paid by
+// following reservations.
+func (b *bucket) Charge(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(time.Now())
+	b.tokens -= float64(n)
+}
+
+// Tokens returns available tokens.
+func (b *bucket) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(time.Now())
+	return b.tokens
+}
@@ -10,6 +10,8 @@ package aio_test
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -62,12 +64,11 @@ func TestLimiter(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 			defer cancel()
 
-			_, err := api.Prompt(ctx, prompt.ToSeq())
+			_, err := api.Prompt(ctx, prompt.ToSeq(), chatter.MaxTokens(1000))
 			return err
 		}
 
-		// +1 due to debt model
-		for range n + 1 {
+		for range n {
 			err := prompt()
 			it.Then(t).Should(it.Nil(err))
 		}
@@ -75,4 +76,55 @@ func TestLimiter(t *testing.T) {
 		err := prompt()
 		it.Then(t).ShouldNot(it.Nil(err))
 	})
+
+	t.Run("Refund", func(t *testing.T) {
+		rpm := 100000
+		tpm := n * 1000
+		api := aio.NewLimiter(rpm, tpm, mockTokensUsage(10))
+
+		prompt := func() error {
+			var prompt chatter.Prompt
+			prompt.WithTask("Make me a test.")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err := api.Prompt(ctx, prompt.ToSeq(), chatter.MaxTokens(1000))
+			return err
+		}
+
+		// reservation of 1000 tokens is refunded down to actual usage
+		for range 10 * n {
+			err := prompt()
+			it.Then(t).Should(it.Nil(err))
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		rpm := 100000
+		tpm := n * 1000
+		api := aio.NewLimiter(rpm, tpm, mockTokensUsage(1000))
+
+		var wg sync.WaitGroup
+		var ok atomic.Int32
+		for range 2 * n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+
+				_, err := api.Prompt(ctx, []chatter.Message{chatter.Text("test")}, chatter.MaxTokens(1000))
+				if err == nil {
+					ok.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		it.Then(t).Should(
+			it.Equal(int(ok.Load()), n),
+		)
+	})
 }
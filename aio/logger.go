@@ -23,6 +23,14 @@ type Logger struct {
 	jsonFormat bool
 }
 
+var (
+	_ chatter.Chatter = (*Logger)(nil)
+	_ chatter.Wrapper = (*Logger)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (deb *Logger) Unwrap() chatter.Chatter { return deb.Chatter }
+
 func NewTextLogger(w io.Writer, chatter chatter.Chatter) *Logger {
 	return &Logger{
 		Chatter:    chatter,
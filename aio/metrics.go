@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"context"
+	"time"
+
+	"github.com/kshard/chatter"
+)
+
+// Measure of single LLM call, reported to the metrics sink.
+type Measure struct {
+	// Model identity
+	Model string
+
+	// Feature tag supplied by the caller through the context, see [WithFeature]
+	Feature string
+
+	// Stage of the reply, empty if the call has failed without reply
+	Stage chatter.Stage
+
+	// Latency of the call
+	Latency time.Duration
+
+	// Usage reported by the reply
+	Usage chatter.Usage
+
+	// Error of the call
+	Err error
+}
+
+// MetricsSink abstracts metrics backend (e.g. Prometheus, expvar).
+// The sink derives request counts, latency histograms, token counters and
+// error counts from the measure.
+type MetricsSink interface {
+	Measure(Measure)
+}
+
+// Metrics of LLM I/O, each call is measured and reported to the sink.
+// Measures are labelled by the model identity, the stage of the reply and
+// optional feature tag from the context.
+//
+//	llm := aio.NewMetrics(aio.NewExpvar("llm"), llm)
+//	reply, err := llm.Prompt(aio.WithFeature(ctx, "summary"), prompt)
+type Metrics struct {
+	chatter.Chatter
+	sink  MetricsSink
+	model string
+}
+
+var (
+	_ chatter.Chatter = (*Metrics)(nil)
+	_ chatter.Wrapper = (*Metrics)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (m *Metrics) Unwrap() chatter.Chatter { return m.Chatter }
+
+// Creates metrics layer for LLM client.
+func NewMetrics(sink MetricsSink, chatter chatter.Chatter) *Metrics {
+	return &Metrics{
+		Chatter: chatter,
+		model:   modelID(chatter),
+		sink:    sink,
+	}
+}
+
+// Set the model identity, it is used as metrics label.
+func (m *Metrics) WithModel(model string) *Metrics {
+	m.model = model
+	return m
+}
+
+func (m *Metrics) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	t := time.Now()
+	reply, err := m.Chatter.Prompt(ctx, prompt, opts...)
+
+	measure := Measure{
+		Model:   m.model,
+		Feature: Feature(ctx),
+		Latency: time.Since(t),
+		Err:     err,
+	}
+	if reply != nil {
+		measure.Stage = reply.Stage
+		measure.Usage = reply.Usage
+	}
+	m.sink.Measure(measure)
+
+	return reply, err
+}
+
+//------------------------------------------------------------------------------
+
+type featureKey struct{}
+
+// WithFeature tags the context with the feature name, the tag labels metrics.
+func WithFeature(ctx context.Context, feature string) context.Context {
+	return context.WithValue(ctx, featureKey{}, feature)
+}
+
+// Feature returns the feature tag of the context, if any.
+func Feature(ctx context.Context) string {
+	if v, ok := ctx.Value(featureKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
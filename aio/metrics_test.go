@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+type sink struct{ seq []aio.Measure }
+
+func (s *sink) Measure(m aio.Measure) { s.seq = append(s.seq, m) }
+
+func TestMetrics(t *testing.T) {
+	reply := &chatter.Reply{
+		Stage:   chatter.LLM_RETURN,
+		Usage:   chatter.Usage{InputTokens: 10, ReplyTokens: 20},
+		Content: []chatter.Content{chatter.Text("ok")},
+	}
+
+	t.Run("Measure", func(t *testing.T) {
+		s := &sink{}
+		llm := aio.NewMetrics(s, mock{reply}).WithModel("test-model")
+
+		_, err := llm.Prompt(aio.WithFeature(context.Background(), "summary"),
+			[]chatter.Message{chatter.Text("ping")},
+		)
+		it.Then(t).Must(it.Nil(err), it.Equal(len(s.seq), 1))
+		it.Then(t).Should(
+			it.Equal(s.seq[0].Model, "test-model"),
+			it.Equal(s.seq[0].Feature, "summary"),
+			it.Equal(s.seq[0].Stage, chatter.LLM_RETURN),
+			it.Equal(s.seq[0].Usage.ReplyTokens, 20),
+			it.Nil(s.seq[0].Err),
+		)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		s := &sink{}
+		llm := aio.NewMetrics(s, faulty{errors.New("failed")})
+
+		_, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+		it.Then(t).Must(it.Fail(func() error { return err }), it.Equal(len(s.seq), 1))
+		it.Then(t).Should(
+			it.Equal(s.seq[0].Stage, ""),
+			it.Equal(s.seq[0].Feature, ""),
+			it.Fail(func() error { return s.seq[0].Err }),
+		)
+	})
+
+	t.Run("Expvar", func(t *testing.T) {
+		s := aio.NewExpvar("chatter_test")
+
+		llm := aio.NewMetrics(s, mock{reply}).WithModel("m")
+		ctx := aio.WithFeature(context.Background(), "f")
+		for i := 0; i < 2; i++ {
+			_, err := llm.Prompt(ctx, []chatter.Message{chatter.Text("ping")})
+			it.Then(t).Must(it.Nil(err))
+		}
+
+		_, err := aio.NewMetrics(s, faulty{errors.New("failed")}).WithModel("m").Prompt(ctx, nil)
+		it.Then(t).Must(it.Fail(func() error { return err }))
+
+		root := expvar.Get("chatter_test").(*expvar.Map)
+		get := func(metric, key string) string {
+			return root.Get(metric).(*expvar.Map).Get(key).String()
+		}
+		it.Then(t).Should(
+			it.Equal(get("requests", "m/f/return"), "2"),
+			it.Equal(get("input_tokens", "m/f/return"), "20"),
+			it.Equal(get("reply_tokens", "m/f/return"), "40"),
+			it.Equal(get("errors", "m/f"), "1"),
+		)
+	})
+}
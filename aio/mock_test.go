@@ -23,3 +23,29 @@ func (mock mock) Usage() chatter.Usage { return mock.reply.Usage }
 func (mock mock) Prompt(context.Context, []chatter.Message, ...chatter.Opt) (*chatter.Reply, error) {
 	return mock.reply, nil
 }
+
+// mock that records the conversation
+type recorder struct {
+	seq   []chatter.Message
+	calls int
+	reply *chatter.Reply
+}
+
+func (mock *recorder) Usage() chatter.Usage { return mock.reply.Usage }
+
+func (mock *recorder) Prompt(ctx context.Context, seq []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	mock.seq = seq
+	mock.calls++
+	return mock.reply, nil
+}
+
+// mock that fails every prompt
+type faulty struct {
+	err error
+}
+
+func (mock faulty) Usage() chatter.Usage { return chatter.Usage{} }
+
+func (mock faulty) Prompt(context.Context, []chatter.Message, ...chatter.Opt) (*chatter.Reply, error) {
+	return nil, mock.err
+}
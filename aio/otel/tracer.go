@@ -0,0 +1,178 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package otel implements OpenTelemetry tracing of LLM I/O.
+//
+//	tracer := provider.Tracer("github.com/kshard/chatter")
+//	llm := otel.NewTracer(tracer, llm)
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kshard/chatter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenTelemetry tracing of LLM I/O. Each prompt is traced as client span
+// annotated with GenAI semantic conventions: model, inference parameters,
+// token usage, finish stage and tools requested by the model.
+//
+//	llm := otel.NewTracer(tracer, llm)
+//
+//	reply, err := llm.Prompt(ctx, prompt)
+//	answer, err := reply.Invoke(llm.Tool(ctx, f))
+type Tracer struct {
+	chatter.Chatter
+	tracer   trace.Tracer
+	model    string
+	provider string
+}
+
+var (
+	_ chatter.Chatter = (*Tracer)(nil)
+	_ chatter.Wrapper = (*Tracer)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (t *Tracer) Unwrap() chatter.Chatter { return t.Chatter }
+
+// Creates tracing layer for LLM client.
+func NewTracer(tracer trace.Tracer, chatter chatter.Chatter) *Tracer {
+	return &Tracer{
+		Chatter: chatter,
+		model:   modelID(chatter),
+		tracer:  tracer,
+	}
+}
+
+// Set the model identity, reported as gen_ai.request.model.
+func (t *Tracer) WithModel(model string) *Tracer {
+	t.model = model
+	return t
+}
+
+// Set the provider name (e.g. aws.bedrock, openai), reported as gen_ai.provider.name.
+func (t *Tracer) WithProvider(provider string) *Tracer {
+	t.provider = provider
+	return t
+}
+
+func (t *Tracer) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	name := "chat"
+	if t.model != "" {
+		name = "chat " + t.model
+	}
+
+	attrs := []attribute.KeyValue{semconv.GenAIOperationNameChat}
+	if t.model != "" {
+		attrs = append(attrs, semconv.GenAIRequestModel(t.model))
+	}
+	if t.provider != "" {
+		attrs = append(attrs, semconv.GenAIProviderNameKey.String(t.provider))
+	}
+	attrs = append(attrs, inferrerAttributes(opts)...)
+
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	reply, err := t.Chatter.Prompt(ctx, prompt, opts...)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		semconv.GenAIResponseFinishReasons(string(reply.Stage)),
+		semconv.GenAIUsageInputTokens(reply.Usage.InputTokens),
+		semconv.GenAIUsageOutputTokens(reply.Usage.ReplyTokens),
+	)
+	if reply.Usage.CacheReadTokens > 0 {
+		span.SetAttributes(semconv.GenAIUsageCacheReadInputTokens(reply.Usage.CacheReadTokens))
+	}
+	if reply.Usage.CacheWriteTokens > 0 {
+		span.SetAttributes(semconv.GenAIUsageCacheCreationInputTokens(reply.Usage.CacheWriteTokens))
+	}
+
+	tools := make([]string, 0)
+	for _, c := range reply.Content {
+		if inv, ok := c.(chatter.Invoke); ok {
+			tools = append(tools, inv.Cmd)
+		}
+	}
+	if len(tools) > 0 {
+		span.SetAttributes(attribute.StringSlice("gen_ai.response.tool_names", tools))
+	}
+
+	if reply.Stage == chatter.LLM_ERROR {
+		span.SetStatus(codes.Error, "llm has aborted execution")
+	}
+
+	return reply, nil
+}
+
+// Tool wraps the tool executor, each tool execution driven by [chatter.Reply.Invoke]
+// is traced as child span of the context.
+func (t *Tracer) Tool(ctx context.Context, f func(string, json.RawMessage) (json.RawMessage, error)) func(string, json.RawMessage) (json.RawMessage, error) {
+	return func(name string, args json.RawMessage) (json.RawMessage, error) {
+		_, span := t.tracer.Start(ctx, "execute_tool "+name,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(
+				semconv.GenAIOperationNameExecuteTool,
+				semconv.GenAIToolName(name),
+			),
+		)
+		defer span.End()
+
+		val, err := f(name, args)
+		if err != nil {
+			recordError(span, err)
+			return nil, err
+		}
+
+		return val, nil
+	}
+}
+
+// GenAI attributes of inference parameters
+func inferrerAttributes(opts []chatter.Opt) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0)
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case chatter.Temperature:
+			attrs = append(attrs, semconv.GenAIRequestTemperature(float64(v)))
+		case chatter.TopP:
+			attrs = append(attrs, semconv.GenAIRequestTopP(float64(v)))
+		case chatter.TopK:
+			attrs = append(attrs, semconv.GenAIRequestTopK(float64(v)))
+		case chatter.MaxTokens:
+			attrs = append(attrs, semconv.GenAIRequestMaxTokens(int(v)))
+		case chatter.StopSequences:
+			attrs = append(attrs, semconv.GenAIRequestStopSequences(v...))
+		}
+	}
+	return attrs
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.SetAttributes(semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err)))
+}
+
+// identity of the model behind the client, the package chatter is shadowed
+// by arguments of constructors.
+func modelID(llm chatter.Chatter) string { return chatter.ModelID(llm) }
@@ -0,0 +1,142 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package otel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	attrs := func(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+		kv := map[attribute.Key]attribute.Value{}
+		for _, a := range span.Attributes {
+			kv[a.Key] = a.Value
+		}
+		return kv
+	}
+
+	t.Run("Prompt", func(t *testing.T) {
+		defer exporter.Reset()
+
+		llm := otel.NewTracer(tracer, mock{&chatter.Reply{
+			Stage: chatter.LLM_INVOKE,
+			Usage: chatter.Usage{InputTokens: 10, ReplyTokens: 20},
+			Content: []chatter.Content{
+				chatter.Invoke{Cmd: "weather", Args: chatter.Json{ID: "1", Value: json.RawMessage(`{}`)}},
+			},
+		}}).WithModel("test-model").WithProvider("test")
+
+		reply, err := llm.Prompt(context.Background(),
+			[]chatter.Message{chatter.Text("ping")},
+			chatter.Temperature(0.5), chatter.MaxTokens(100),
+		)
+		it.Then(t).Must(it.Nil(err))
+
+		_, err = reply.Invoke(llm.Tool(context.Background(),
+			func(string, json.RawMessage) (json.RawMessage, error) { return json.RawMessage(`{}`), nil },
+		))
+		it.Then(t).Must(it.Nil(err))
+
+		spans := exporter.GetSpans()
+		it.Then(t).Must(it.Equal(len(spans), 2))
+
+		chat := attrs(spans[0])
+		it.Then(t).Should(
+			it.Equal(spans[0].Name, "chat test-model"),
+			it.Equal(chat["gen_ai.operation.name"].AsString(), "chat"),
+			it.Equal(chat["gen_ai.provider.name"].AsString(), "test"),
+			it.Equal(chat["gen_ai.request.model"].AsString(), "test-model"),
+			it.Equal(chat["gen_ai.request.temperature"].AsFloat64(), 0.5),
+			it.Equal(chat["gen_ai.request.max_tokens"].AsInt64(), 100),
+			it.Equal(chat["gen_ai.usage.input_tokens"].AsInt64(), 10),
+			it.Equal(chat["gen_ai.usage.output_tokens"].AsInt64(), 20),
+			it.Seq(chat["gen_ai.response.finish_reasons"].AsStringSlice()).Equal("invoke"),
+			it.Seq(chat["gen_ai.response.tool_names"].AsStringSlice()).Equal("weather"),
+		)
+
+		tool := attrs(spans[1])
+		it.Then(t).Should(
+			it.Equal(spans[1].Name, "execute_tool weather"),
+			it.Equal(tool["gen_ai.operation.name"].AsString(), "execute_tool"),
+			it.Equal(tool["gen_ai.tool.name"].AsString(), "weather"),
+		)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		defer exporter.Reset()
+
+		llm := otel.NewTracer(tracer, faulty{errors.New("failed")})
+
+		_, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("ping")})
+		it.Then(t).ShouldNot(it.Nil(err))
+
+		spans := exporter.GetSpans()
+		it.Then(t).Must(it.Equal(len(spans), 1))
+		it.Then(t).Should(
+			it.Equal(spans[0].Name, "chat"),
+			it.Equal(spans[0].Status.Code, codes.Error),
+			it.Equal(len(spans[0].Events), 1),
+		)
+	})
+
+	t.Run("ToolError", func(t *testing.T) {
+		defer exporter.Reset()
+
+		llm := otel.NewTracer(tracer, mock{})
+		f := llm.Tool(context.Background(),
+			func(string, json.RawMessage) (json.RawMessage, error) { return nil, errors.New("failed") },
+		)
+
+		_, err := f("weather", json.RawMessage(`{}`))
+		it.Then(t).ShouldNot(it.Nil(err))
+
+		spans := exporter.GetSpans()
+		it.Then(t).Must(it.Equal(len(spans), 1))
+		it.Then(t).Should(
+			it.Equal(spans[0].Status.Code, codes.Error),
+		)
+	})
+}
+
+// mock llm
+type mock struct {
+	reply *chatter.Reply
+}
+
+func (mock mock) Usage() chatter.Usage { return chatter.Usage{} }
+
+func (mock mock) Prompt(context.Context, []chatter.Message, ...chatter.Opt) (*chatter.Reply, error) {
+	return mock.reply, nil
+}
+
+// mock llm that always fails
+type faulty struct {
+	err error
+}
+
+func (mock faulty) Usage() chatter.Usage { return chatter.Usage{} }
+
+func (mock faulty) Prompt(context.Context, []chatter.Message, ...chatter.Opt) (*chatter.Reply, error) {
+	return nil, mock.err
+}
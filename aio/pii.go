@@ -0,0 +1,419 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kshard/chatter"
+)
+
+// Detector of personally identifiable information (PII) in the text.
+type Detector interface {
+	// Kind of PII, used as placeholder prefix (e.g. EMAIL)
+	Kind() string
+
+	// Find returns index pairs of detected PII within the text,
+	// using the same convention as regexp.FindAllStringIndex.
+	Find(text string) [][]int
+}
+
+// Creates detector of PII matching the regular expression.
+func NewRegexpDetector(kind string, re *regexp.Regexp) Detector {
+	return regexpDetector{kind: kind, re: re}
+}
+
+type regexpDetector struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+func (d regexpDetector) Kind() string { return d.kind }
+
+func (d regexpDetector) Find(text string) [][]int { return d.re.FindAllStringIndex(text, -1) }
+
+// Creates detector of names (e.g. customers, employees) matching whole words.
+// Empty names are skipped.
+func NewNameDetector(names ...string) Detector {
+	sorted := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != "" {
+			sorted = append(sorted, name)
+		}
+	}
+
+	if len(sorted) == 0 {
+		return regexpDetector{kind: "NAME", re: regexp.MustCompile(`$^`)}
+	}
+
+	// longest names first, so that full names win over its parts
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	seq := make([]string, len(sorted))
+	for i, name := range sorted {
+		// word boundary is only defined next to word characters
+		seq[i] = regexp.QuoteMeta(name)
+		if isWordChar(name[0]) {
+			seq[i] = `\b` + seq[i]
+		}
+		if isWordChar(name[len(name)-1]) {
+			seq[i] = seq[i] + `\b`
+		}
+	}
+
+	return regexpDetector{
+		kind: "NAME",
+		re:   regexp.MustCompile(strings.Join(seq, "|")),
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+var (
+	// Detector of e-mail addresses
+	EmailDetector = NewRegexpDetector("EMAIL",
+		regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	)
+
+	// Detector of phone numbers, either international (+358 40 123 4567)
+	// or local with separators (040-123-4567, (555) 123-4567). Digits
+	// separated by dots (e.g. IP addresses, versions) are not matched.
+	PhoneDetector = NewRegexpDetector("PHONE",
+		regexp.MustCompile(`(?:\+\d{1,3}[\s-]?\(?\d{1,4}\)?(?:[\s-]?\d{2,4}){2,4}\b)|(?:(?:\(\d{2,4}\)|\b\d{2,4})[\s-]\d{3,4}[\s-]\d{3,4}\b)`),
+	)
+
+	// Detector of IBANs, candidates are validated by the checksum
+	IBANDetector Detector = ibanDetector{
+		re: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+	}
+)
+
+type ibanDetector struct{ re *regexp.Regexp }
+
+func (d ibanDetector) Kind() string { return "IBAN" }
+
+func (d ibanDetector) Find(text string) [][]int {
+	seq := make([][]int, 0)
+	for _, loc := range d.re.FindAllStringIndex(text, -1) {
+		if isIBAN(text[loc[0]:loc[1]]) {
+			seq = append(seq, loc)
+		}
+	}
+	return seq
+}
+
+// validates IBAN checksum (ISO 13616, mod 97)
+func isIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+
+	var sb strings.Builder
+	for _, r := range s[4:] + s[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&sb, "%d", r-'A'+10)
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(sb.String(), 10)
+	if !ok {
+		return false
+	}
+
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// DefaultDetectors is the set of PII detectors used by default.
+var DefaultDetectors = []Detector{EmailDetector, IBANDetector, PhoneDetector}
+
+//------------------------------------------------------------------------------
+
+// PII redaction for LLM I/O. The detected PII within text, prompt blocks and
+// answers of tools is replaced with stable placeholders (e.g. [EMAIL_1])
+// before calling LLM, the originals are restored in the reply. Placeholders
+// are stable within the conversation, the same value is always replaced with
+// the same placeholder as long as the conversation grows by appending messages.
+// Reasoning signed by the provider is passed as-is in both directions, the
+// signature covers the text and the provider rejects modified blocks. Its text
+// keeps placeholders written by the model, the originals are not restored.
+//
+//	llm := aio.NewPII(llm).WithDetectors(
+//		aio.EmailDetector,
+//		aio.NewNameDetector("Alice", "Bob"),
+//	)
+type PII struct {
+	chatter.Chatter
+	detectors []Detector
+}
+
+var (
+	_ chatter.Chatter = (*PII)(nil)
+	_ chatter.Wrapper = (*PII)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (p *PII) Unwrap() chatter.Chatter { return p.Chatter }
+
+// Creates PII redaction layer for LLM client, using [DefaultDetectors].
+func NewPII(chatter chatter.Chatter) *PII {
+	return &PII{
+		Chatter:   chatter,
+		detectors: DefaultDetectors,
+	}
+}
+
+// Set the detectors of PII, replacing default ones.
+func (p *PII) WithDetectors(detectors ...Detector) *PII {
+	p.detectors = detectors
+	return p
+}
+
+func (p *PII) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	v := &vault{
+		detectors:   p.detectors,
+		placeholder: make(map[string]string),
+		original:    make(map[string]string),
+		counter:     make(map[string]int),
+	}
+
+	seq := make([]chatter.Message, len(prompt))
+	for i, msg := range prompt {
+		seq[i] = v.redactMessage(msg)
+	}
+
+	reply, err := p.Chatter.Prompt(ctx, seq, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.restoreReply(reply), nil
+}
+
+//------------------------------------------------------------------------------
+
+// vault of placeholders for single call
+type vault struct {
+	detectors   []Detector
+	placeholder map[string]string
+	original    map[string]string
+	counter     map[string]int
+	restorer    *strings.Replacer
+}
+
+func (v *vault) redact(text string) string {
+	type match struct {
+		kind       string
+		start, end int
+	}
+
+	seq := make([]match, 0)
+	for _, d := range v.detectors {
+		for _, loc := range d.Find(text) {
+			seq = append(seq, match{kind: d.Kind(), start: loc[0], end: loc[1]})
+		}
+	}
+	if len(seq) == 0 {
+		return text
+	}
+
+	// earliest and longest match wins
+	sort.SliceStable(seq, func(i, j int) bool {
+		if seq[i].start != seq[j].start {
+			return seq[i].start < seq[j].start
+		}
+		return seq[i].end > seq[j].end
+	})
+
+	var sb strings.Builder
+	at := 0
+	for _, m := range seq {
+		if m.start < at {
+			continue
+		}
+		sb.WriteString(text[at:m.start])
+		sb.WriteString(v.placeholderOf(m.kind, text[m.start:m.end]))
+		at = m.end
+	}
+	sb.WriteString(text[at:])
+
+	return sb.String()
+}
+
+func (v *vault) placeholderOf(kind, value string) string {
+	if p, has := v.placeholder[value]; has {
+		return p
+	}
+
+	v.counter[kind]++
+	p := fmt.Sprintf("[%s_%d]", kind, v.counter[kind])
+	v.placeholder[value] = p
+	v.original[p] = value
+	v.restorer = nil
+
+	return p
+}
+
+// redacts raw JSON, PII is matched within the encoded text
+func (v *vault) redactJson(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	return json.RawMessage(v.redact(string(raw)))
+}
+
+func (v *vault) restore(text string) string {
+	if len(v.original) == 0 {
+		return text
+	}
+
+	if v.restorer == nil {
+		seq := make([]string, 0, 2*len(v.original))
+		for p, o := range v.original {
+			seq = append(seq, p, o)
+		}
+		v.restorer = strings.NewReplacer(seq...)
+	}
+
+	return v.restorer.Replace(text)
+}
+
+// restores raw JSON, originals are escaped as JSON strings
+func (v *vault) restoreJson(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 || len(v.original) == 0 {
+		return raw
+	}
+
+	seq := make([]string, 0, 2*len(v.original))
+	for p, o := range v.original {
+		b, _ := json.Marshal(o)
+		seq = append(seq, p, string(b[1:len(b)-1]))
+	}
+
+	return json.RawMessage(strings.NewReplacer(seq...).Replace(string(raw)))
+}
+
+func (v *vault) redactMessage(msg chatter.Message) chatter.Message {
+	switch m := msg.(type) {
+	case chatter.Stratum:
+		return chatter.Stratum(v.redact(string(m)))
+	case chatter.Text:
+		return chatter.Text(v.redact(string(m)))
+	case *chatter.Prompt:
+		prompt := &chatter.Prompt{
+			Task:    chatter.Task(v.redact(string(m.Task))),
+			Content: make([]chatter.Content, len(m.Content)),
+		}
+		for i, c := range m.Content {
+			prompt.Content[i] = v.redactContent(c)
+		}
+		return prompt
+	case *chatter.Answer:
+		answer := &chatter.Answer{Yield: make([]chatter.Json, len(m.Yield))}
+		for i, y := range m.Yield {
+			answer.Yield[i] = chatter.Json{ID: y.ID, Source: y.Source, Value: v.redactJson(y.Value)}
+		}
+		return answer
+	case *chatter.Reply:
+		reply := &chatter.Reply{Stage: m.Stage, Usage: m.Usage, Cached: m.Cached, Content: make([]chatter.Content, len(m.Content))}
+		for i, c := range m.Content {
+			reply.Content[i] = v.redactContent(c)
+		}
+		return reply
+	default:
+		return msg
+	}
+}
+
+func (v *vault) redactContent(c chatter.Content) chatter.Content {
+	seq := func(s []string) []string {
+		out := make([]string, len(s))
+		for i, x := range s {
+			out[i] = v.redact(x)
+		}
+		return out
+	}
+
+	switch x := c.(type) {
+	case chatter.Text:
+		return chatter.Text(v.redact(string(x)))
+	case chatter.Json:
+		return chatter.Json{ID: x.ID, Source: x.Source, Value: v.redactJson(x.Value)}
+	case chatter.Guide:
+		return chatter.Guide{Note: v.redact(x.Note), Text: seq(x.Text)}
+	case chatter.Rules:
+		return chatter.Rules{Note: v.redact(x.Note), Text: seq(x.Text)}
+	case chatter.Feedback:
+		return chatter.Feedback{Note: v.redact(x.Note), Text: seq(x.Text)}
+	case chatter.Example:
+		return chatter.Example{Input: v.redact(x.Input), Reply: v.redact(x.Reply)}
+	case chatter.Context:
+		return chatter.Context{Note: v.redact(x.Note), Text: seq(x.Text)}
+	case chatter.Input:
+		return chatter.Input{Note: v.redact(x.Note), Text: seq(x.Text)}
+	case chatter.Blob:
+		return chatter.Blob{Note: v.redact(x.Note), Text: v.redact(x.Text)}
+	case chatter.Invoke:
+		// the original provider message is dropped, it contains PII in plain form
+		return chatter.Invoke{
+			Cmd:  x.Cmd,
+			Args: chatter.Json{ID: x.Args.ID, Source: x.Args.Source, Value: v.redactJson(x.Args.Value)},
+		}
+	case chatter.Reasoning:
+		// signed reasoning is replayed to the provider unmodified
+		if x.Signature != "" || len(x.Redacted) != 0 {
+			return c
+		}
+		return chatter.Reasoning{Text: v.redact(x.Text)}
+	default:
+		return c
+	}
+}
+
+func (v *vault) restoreReply(reply *chatter.Reply) *chatter.Reply {
+	out := &chatter.Reply{Stage: reply.Stage, Usage: reply.Usage, Cached: reply.Cached, Content: make([]chatter.Content, len(reply.Content))}
+
+	for i, c := range reply.Content {
+		switch x := c.(type) {
+		case chatter.Text:
+			out.Content[i] = chatter.Text(v.restore(string(x)))
+		case chatter.Json:
+			out.Content[i] = chatter.Json{ID: x.ID, Source: x.Source, Value: v.restoreJson(x.Value)}
+		case chatter.Invoke:
+			out.Content[i] = chatter.Invoke{
+				Cmd:     x.Cmd,
+				Args:    chatter.Json{ID: x.Args.ID, Source: x.Args.Source, Value: v.restoreJson(x.Args.Value)},
+				Message: x.Message,
+			}
+		case chatter.Reasoning:
+			if x.Signature != "" || len(x.Redacted) != 0 {
+				out.Content[i] = c
+				continue
+			}
+			out.Content[i] = chatter.Reasoning{Text: v.restore(x.Text)}
+		default:
+			out.Content[i] = c
+		}
+	}
+
+	return out
+}
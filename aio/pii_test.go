@@ -0,0 +1,149 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+func TestPII(t *testing.T) {
+	t.Run("Redact", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN}}
+		pii := aio.NewPII(llm).WithDetectors(
+			append(aio.DefaultDetectors, aio.NewNameDetector("Alice Smith", "Alice"))...,
+		)
+
+		_, err := pii.Prompt(context.Background(), []chatter.Message{
+			chatter.Text("Alice Smith <alice@example.com>, +358 40 123 4567"),
+			&chatter.Prompt{
+				Task: "Transfer money from alice@example.com",
+				Content: []chatter.Content{
+					chatter.Input{Note: "account", Text: []string{"FI21 1234 5600 0007 85", "DE00 ABCD EFGH IJKL MN"}},
+				},
+			},
+			&chatter.Answer{Yield: []chatter.Json{
+				{ID: "1", Source: "crm", Value: json.RawMessage(`{"name":"Alice"}`)},
+			}},
+		})
+		it.Then(t).Must(it.Nil(err))
+
+		it.Then(t).Should(
+			it.Equal(llm.seq[0].String(), "[NAME_1] <[EMAIL_1]>, [PHONE_1]"),
+			it.Equal(llm.seq[1].(*chatter.Prompt).Task, "Transfer money from [EMAIL_1]"),
+			it.Seq(llm.seq[1].(*chatter.Prompt).Content[0].(chatter.Input).Text).Equal(
+				"[IBAN_1]", "DE00 ABCD EFGH IJKL MN",
+			),
+			it.Equal(string(llm.seq[2].(*chatter.Answer).Yield[0].Value), `{"name":"[NAME_2]"}`),
+		)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{
+			Stage: chatter.LLM_INVOKE,
+			Content: []chatter.Content{
+				chatter.Text("Sending mail to [EMAIL_1]"),
+				chatter.Invoke{Cmd: "mail", Args: chatter.Json{ID: "1", Value: json.RawMessage(`{"to":"[NAME_1] <[EMAIL_1]>"}`)}},
+			},
+		}}
+		pii := aio.NewPII(llm).WithDetectors(aio.EmailDetector, aio.NewNameDetector(`Bob "Bobby"`))
+
+		reply, err := pii.Prompt(context.Background(), []chatter.Message{
+			chatter.Text(`Send mail to Bob "Bobby" bob@example.com`),
+		})
+		it.Then(t).Must(it.Nil(err))
+
+		it.Then(t).Should(
+			it.Equal(llm.seq[0].String(), "Send mail to [NAME_1] [EMAIL_1]"),
+			it.Equal(reply.String(), "Sending mail to bob@example.com"),
+			it.Equal(string(reply.Content[1].(chatter.Invoke).Args.Value), `{"to":"Bob \"Bobby\" <bob@example.com>"}`),
+		)
+	})
+
+	t.Run("Stable", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN}}
+		pii := aio.NewPII(llm)
+
+		history := []chatter.Message{
+			chatter.Text("a@example.com"),
+			&chatter.Reply{Content: []chatter.Content{chatter.Text("a@example.com")}},
+			chatter.Text("b@example.com and a@example.com"),
+		}
+		_, err := pii.Prompt(context.Background(), history)
+		it.Then(t).Must(it.Nil(err))
+
+		it.Then(t).Should(
+			it.Equal(llm.seq[0].String(), "[EMAIL_1]"),
+			it.Equal(llm.seq[1].String(), "[EMAIL_1]"),
+			it.Equal(llm.seq[2].String(), "[EMAIL_2] and [EMAIL_1]"),
+		)
+	})
+
+	t.Run("Reasoning", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{
+			Stage: chatter.LLM_RETURN,
+			Content: []chatter.Content{
+				chatter.Reasoning{Text: "Reply to [EMAIL_1]", Signature: "sig"},
+				chatter.Reasoning{Text: "Mail [EMAIL_1]"},
+				chatter.Text("Hello [EMAIL_1]"),
+			},
+		}}
+		pii := aio.NewPII(llm)
+
+		reply, err := pii.Prompt(context.Background(), []chatter.Message{
+			chatter.Text("a@example.com"),
+			&chatter.Reply{Content: []chatter.Content{
+				chatter.Reasoning{Text: "Mail a@example.com", Signature: "sig"},
+				chatter.Reasoning{Text: "Mail a@example.com"},
+			}},
+		})
+		it.Then(t).Must(it.Nil(err))
+
+		history := llm.seq[1].(*chatter.Reply)
+		it.Then(t).Should(
+			it.Equiv(history.Content[0], chatter.Content(chatter.Reasoning{Text: "Mail a@example.com", Signature: "sig"})),
+			it.Equiv(history.Content[1], chatter.Content(chatter.Reasoning{Text: "Mail [EMAIL_1]"})),
+			it.Equiv(reply.Content[0], chatter.Content(chatter.Reasoning{Text: "Reply to [EMAIL_1]", Signature: "sig"})),
+			it.Equiv(reply.Content[1], chatter.Content(chatter.Reasoning{Text: "Mail a@example.com"})),
+			it.Equal(reply.Content[2].String(), "Hello a@example.com"),
+		)
+	})
+}
+
+func TestPhoneDetector(t *testing.T) {
+	for text, expect := range map[string]int{
+		"+358 40 123 4567":        1,
+		"+1-555-123-4567":         1,
+		"call 040-123-4567 today": 1,
+		"(555) 123-4567":          1,
+		"192.168.100.1":           0,
+		"10.0.0.1":                0,
+		"version 1.25.0":          0,
+		"2026.10.19":              0,
+		"555.123.4567":            0,
+	} {
+		t.Run(text, func(t *testing.T) {
+			it.Then(t).Should(
+				it.Equal(len(aio.PhoneDetector.Find(text)), expect),
+			)
+		})
+	}
+}
+
+func TestNameDetector(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal(len(aio.NewNameDetector("").Find("Alice")), 0),
+		it.Equal(len(aio.NewNameDetector("", "Alice").Find("Alice and Bob")), 1),
+	)
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package prometheus implements metrics sink of aio.Metrics for Prometheus.
+//
+//	sink, err := prometheus.New(prom.DefaultRegisterer, "llm")
+//	llm := aio.NewMetrics(sink, llm)
+package prometheus
+
+import (
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Sink publishes measures as Prometheus metrics, labelled by model,
+// feature and stage:
+//
+//	{namespace}_requests_total{model,feature,stage}
+//	{namespace}_errors_total{model,feature,stage}
+//	{namespace}_latency_seconds{model,feature,stage}
+//	{namespace}_tokens_total{model,feature,stage,kind}
+//
+// The kind of tokens is one of input, reply, cache_read, cache_write, reasoning.
+// Errors are labelled by the stage of the reply, if any, or "error" otherwise.
+type Sink struct {
+	requests *prom.CounterVec
+	errors   *prom.CounterVec
+	latency  *prom.HistogramVec
+	tokens   *prom.CounterVec
+}
+
+var _ aio.MetricsSink = (*Sink)(nil)
+
+// Creates Prometheus metrics sink, metrics are registered at the registerer.
+func New(reg prom.Registerer, namespace string) (*Sink, error) {
+	s := &Sink{
+		requests: prom.NewCounterVec(
+			prom.CounterOpts{
+				Namespace: namespace,
+				Name:      "requests_total",
+				Help:      "Number of LLM replies.",
+			},
+			[]string{"model", "feature", "stage"},
+		),
+		errors: prom.NewCounterVec(
+			prom.CounterOpts{
+				Namespace: namespace,
+				Name:      "errors_total",
+				Help:      "Number of failed LLM calls.",
+			},
+			[]string{"model", "feature", "stage"},
+		),
+		latency: prom.NewHistogramVec(
+			prom.HistogramOpts{
+				Namespace: namespace,
+				Name:      "latency_seconds",
+				Help:      "Latency of LLM replies.",
+				Buckets:   []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 40, 80, 160},
+			},
+			[]string{"model", "feature", "stage"},
+		),
+		tokens: prom.NewCounterVec(
+			prom.CounterOpts{
+				Namespace: namespace,
+				Name:      "tokens_total",
+				Help:      "Number of tokens consumed by LLM replies.",
+			},
+			[]string{"model", "feature", "stage", "kind"},
+		),
+	}
+
+	for _, c := range []prom.Collector{s.requests, s.errors, s.latency, s.tokens} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Sink) Measure(m aio.Measure) {
+	stage := string(m.Stage)
+	if m.Err != nil {
+		if stage == "" {
+			stage = string(chatter.LLM_ERROR)
+		}
+		s.errors.WithLabelValues(m.Model, m.Feature, stage).Inc()
+		return
+	}
+
+	s.requests.WithLabelValues(m.Model, m.Feature, stage).Inc()
+	s.latency.WithLabelValues(m.Model, m.Feature, stage).Observe(m.Latency.Seconds())
+
+	for kind, n := range map[string]int{
+		"input":       m.Usage.InputTokens,
+		"reply":       m.Usage.ReplyTokens,
+		"cache_read":  m.Usage.CacheReadTokens,
+		"cache_write": m.Usage.CacheWriteTokens,
+		"reasoning":   m.Usage.ReasoningTokens,
+	} {
+		if n > 0 {
+			s.tokens.WithLabelValues(m.Model, m.Feature, stage, kind).Add(float64(n))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package prometheus_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+	"github.com/kshard/chatter/aio/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSink(t *testing.T) {
+	reg := prom.NewRegistry()
+	sink, err := prometheus.New(reg, "llm")
+	it.Then(t).Must(it.Nil(err))
+
+	sink.Measure(aio.Measure{
+		Model:   "m",
+		Feature: "f",
+		Stage:   chatter.LLM_RETURN,
+		Latency: time.Second,
+		Usage:   chatter.Usage{InputTokens: 10, ReplyTokens: 20},
+	})
+	sink.Measure(aio.Measure{Model: "m", Feature: "f", Err: errors.New("failed")})
+	sink.Measure(aio.Measure{Model: "m", Feature: "f", Stage: chatter.LLM_GUARDRAIL, Err: errors.New("failed")})
+
+	it.Then(t).Should(
+		it.Equal(testutil.CollectAndCount(reg, "llm_requests_total"), 1),
+		it.Equal(testutil.CollectAndCount(reg, "llm_tokens_total"), 2),
+		it.Equal(testutil.CollectAndCount(reg, "llm_latency_seconds"), 1),
+		it.Equal(testutil.CollectAndCount(reg, "llm_errors_total"), 2),
+	)
+
+	errs, err := reg.Gather()
+	it.Then(t).Must(it.Nil(err))
+	stages := []string{}
+	for _, mf := range errs {
+		if mf.GetName() != "llm_errors_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "stage" {
+					stages = append(stages, l.GetValue())
+				}
+			}
+		}
+	}
+	it.Then(t).Should(
+		it.Seq(stages).Contain("error", "guardrail"),
+	)
+
+	_, err = prometheus.New(reg, "llm")
+	it.Then(t).ShouldNot(it.Nil(err))
+}
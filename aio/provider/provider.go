@@ -37,6 +37,40 @@ type Inferrer struct {
 	TopK          float64
 	MaxTokens     int
 	StopSequences []string
+
+	ReasoningEffort string
+	ReasoningBudget int
+}
+
+// token budget of reasoning efforts
+var reasoningBudget = map[string]int{
+	string(chatter.ReasoningLow):    1024,
+	string(chatter.ReasoningMedium): 4096,
+	string(chatter.ReasoningHigh):   16384,
+}
+
+// Reasoning returns the effort and the token budget of reasoning, one is derived
+// from another if only one is defined. Empty effort and zero budget means
+// reasoning is not requested.
+func (inf Inferrer) Reasoning() (string, int) {
+	effort, budget := inf.ReasoningEffort, inf.ReasoningBudget
+
+	if budget == 0 {
+		budget = reasoningBudget[effort]
+	}
+
+	if effort == "" && budget > 0 {
+		switch {
+		case budget <= reasoningBudget[string(chatter.ReasoningLow)]:
+			effort = string(chatter.ReasoningLow)
+		case budget <= reasoningBudget[string(chatter.ReasoningMedium)]:
+			effort = string(chatter.ReasoningMedium)
+		default:
+			effort = string(chatter.ReasoningHigh)
+		}
+	}
+
+	return effort, budget
 }
 
 // LLM request encoder.
@@ -49,6 +83,7 @@ type Encoder[A any] interface {
 	AsPrompt(*chatter.Prompt) error
 	AsAnswer(*chatter.Answer) error
 	AsReply(*chatter.Reply) error
+	AsCachePoint(chatter.CachePoint) error
 
 	Build() A
 }
@@ -68,11 +103,15 @@ type Provider[A, B any] struct {
 	factory Factory[A]
 	decoder Decoder[B]
 	service Service[A, B]
+	model   string
 
-	usage chatter.Usage
+	usage chatter.Meter
 }
 
-var _ chatter.Chatter = (*Provider[any, any])(nil)
+var (
+	_ chatter.Chatter       = (*Provider[any, any])(nil)
+	_ chatter.UsageResetter = (*Provider[any, any])(nil)
+)
 
 func New[A, B any](
 	factory Factory[A],
@@ -86,7 +125,17 @@ func New[A, B any](
 	}
 }
 
-func (p *Provider[A, B]) Usage() chatter.Usage { return p.usage }
+// Set the model identity, as defined by the vendor.
+func (p *Provider[A, B]) WithModel(model string) *Provider[A, B] {
+	p.model = model
+	return p
+}
+
+// Model ID as defined by the vendor
+func (p *Provider[A, B]) ModelID() string { return p.model }
+
+func (p *Provider[A, B]) Usage() chatter.Usage      { return p.usage.Usage() }
+func (p *Provider[A, B]) ResetUsage() chatter.Usage { return p.usage.Reset() }
 
 func (p *Provider[A, B]) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
 	if len(prompt) == 0 {
@@ -114,6 +163,10 @@ func (p *Provider[A, B]) Prompt(ctx context.Context, prompt []chatter.Message, o
 			case chatter.StopSequences:
 				config.StopSequences = make([]string, len(v))
 				copy(config.StopSequences, v)
+			case chatter.ReasoningEffort:
+				config.ReasoningEffort = string(v)
+			case chatter.ReasoningBudget:
+				config.ReasoningBudget = int(v)
 			case chatter.Registry:
 				for _, cmd := range v {
 					input.WithCommand(cmd)
@@ -145,6 +198,10 @@ func (p *Provider[A, B]) Prompt(ctx context.Context, prompt []chatter.Message, o
 			if err := input.AsReply(v); err != nil {
 				return nil, ErrBadRequest.With(err)
 			}
+		case chatter.CachePoint:
+			if err := input.AsCachePoint(v); err != nil {
+				return nil, ErrBadRequest.With(err)
+			}
 		default:
 			return nil, ErrBadRequest.With(fmt.Errorf("unsupported message type %T", term))
 		}
@@ -161,8 +218,7 @@ func (p *Provider[A, B]) Prompt(ctx context.Context, prompt []chatter.Message, o
 		return nil, ErrServiceIO.With(err)
 	}
 
-	p.usage.InputTokens += reply.Usage.InputTokens
-	p.usage.ReplyTokens += reply.Usage.ReplyTokens
+	p.usage.Add(reply.Usage)
 
 	return reply, nil
 }
@@ -11,6 +11,7 @@ package provider_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/fogfish/it/v2"
@@ -85,6 +86,14 @@ func (e *mockEncoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+func (e *mockEncoder) AsCachePoint(chatter.CachePoint) error {
+	if e.input.error != nil {
+		return e.input.error
+	}
+	e.input.messages = append(e.input.messages, "cachepoint")
+	return nil
+}
+
 func (e *mockEncoder) Build() *mockInput {
 	return e.input
 }
@@ -240,6 +249,7 @@ func TestProvider_PromptMessageTypes(t *testing.T) {
 
 	messages := []chatter.Message{
 		chatter.Stratum("system role"),
+		chatter.CachePoint{},
 		chatter.Text("user text"),
 		prompt,
 		answer,
@@ -403,3 +413,49 @@ func TestProvider_Usage(t *testing.T) {
 		it.Equal(usage.ReplyTokens, 84),
 	)
 }
+
+func TestProvider_ModelID(t *testing.T) {
+	factory := func() (provider.Encoder[*mockInput], error) {
+		return (&mockFactory{}).Create()
+	}
+
+	p := provider.New(factory, &mockDecoder{}, &mockService{}).WithModel("test-model")
+
+	it.Then(t).Should(
+		it.Equal(p.ModelID(), "test-model"),
+		it.Equal(chatter.ModelID(p), "test-model"),
+	)
+}
+
+func TestProvider_UsageConcurrent(t *testing.T) {
+	factory := func() (provider.Encoder[*mockInput], error) {
+		return (&mockFactory{}).Create()
+	}
+	decoder := &mockDecoder{}
+	service := &mockService{
+		output: &mockOutput{
+			content: "test",
+			tokens:  chatter.Usage{InputTokens: 1, ReplyTokens: 2},
+		},
+	}
+
+	p := provider.New(factory, decoder, service)
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Prompt(context.Background(), []chatter.Message{chatter.Text("test")})
+		}()
+	}
+	wg.Wait()
+
+	usage := p.ResetUsage()
+	it.Then(t).Should(
+		it.Equal(usage.InputTokens, 100),
+		it.Equal(usage.ReplyTokens, 200),
+		it.Equal(p.Usage().InputTokens, 0),
+		it.Equal(p.Usage().ReplyTokens, 0),
+	)
+}
@@ -0,0 +1,465 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package providertest implements conformance test kit for LLM providers.
+// The kit runs the standard suite against any pair of [provider.Encoder] and
+// [provider.Decoder], covering stratum handling, multi-turn order, reply replay,
+// tool round-trips, options, stop reasons, reasoning and cache checkpoints.
+// Capabilities that are silently ignored by the provider are reported as
+// dropped, incorrect behavior fails the test.
+//
+// The provider supplies adapters between its protocol and the neutral form:
+//
+//	func TestConformance(t *testing.T) {
+//		report := providertest.Run(t, providertest.Kit[*input, *reply]{
+//			Factory:  factory("model"),
+//			Decoder:  decoder{},
+//			Inspect:  func(req *input) providertest.Request { ... },
+//			Response: func(r providertest.Response) (*reply, error) { ... },
+//		})
+//		report.Expect(t, providertest.Stratum, providertest.MultiTurn)
+//	}
+package providertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio/provider"
+)
+
+// Message of the request in the neutral form.
+type Message struct {
+	// Role of the message: system, user, assistant or tool
+	Role string
+
+	// Text of the message
+	Text string
+
+	// Names of tools invoked by the assistant
+	Invoke []string
+
+	// Identifiers of tool results
+	Yield []string
+
+	// Reasoning replayed by the assistant
+	Reasoning []chatter.Reasoning
+}
+
+// Request in the neutral form, produced by the provider's adapter from the
+// request built by encoder.
+type Request struct {
+	// System prompt, if the provider supports it natively
+	System string
+
+	// Sequence of messages
+	Messages []Message
+
+	// Names of tools registered within the request
+	Tools []string
+
+	// Inference parameters encoded into the request
+	Inferrer provider.Inferrer
+
+	// Number of cache checkpoints within the request
+	CachePoints int
+}
+
+// Response in the neutral form, the provider's adapter builds the protocol
+// specific response for decoder from it.
+type Response struct {
+	Stage     chatter.Stage
+	Text      string
+	Invoke    []chatter.Invoke
+	Reasoning []chatter.Reasoning
+	Usage     chatter.Usage
+}
+
+// Kit adapts the provider to the suite.
+type Kit[A, B any] struct {
+	// Factory of the provider's encoder
+	Factory provider.Factory[A]
+
+	// Provider's decoder
+	Decoder provider.Decoder[B]
+
+	// Inspect converts request into neutral form
+	Inspect func(A) Request
+
+	// Response builds provider's response from neutral form,
+	// it returns error if the response is not expressible by the protocol.
+	Response func(Response) (B, error)
+}
+
+// Capability of the provider.
+type Capability string
+
+const (
+	Stratum          = Capability("stratum")
+	MultiTurn        = Capability("multi-turn")
+	ReplyReplay      = Capability("reply-replay")
+	Tools            = Capability("tools")
+	ToolInvokeReplay = Capability("tool-invoke-replay")
+	ToolAnswer       = Capability("tool-answer")
+	ToolInvoke       = Capability("tool-invoke")
+	Temperature      = Capability("option-temperature")
+	TopP             = Capability("option-top-p")
+	MaxTokens        = Capability("option-max-tokens")
+	StopSequences    = Capability("option-stop-sequences")
+	StopReturn       = Capability("stop-return")
+	StopIncomplete   = Capability("stop-incomplete")
+	StopInvoke       = Capability("stop-invoke")
+	Usage            = Capability("usage")
+	Reasoning        = Capability("reasoning")
+	ReasoningReplay  = Capability("reasoning-replay")
+	ReasoningOption  = Capability("option-reasoning")
+	CachePoint       = Capability("cache-point")
+)
+
+// Report of capabilities, true if the capability is supported,
+// false if it is dropped by the provider.
+type Report map[Capability]bool
+
+// Supported capabilities
+func (r Report) Supported() []Capability { return r.filter(true) }
+
+// Dropped capabilities
+func (r Report) Dropped() []Capability { return r.filter(false) }
+
+func (r Report) filter(v bool) []Capability {
+	seq := make([]Capability, 0)
+	for c, ok := range r {
+		if ok == v {
+			seq = append(seq, c)
+		}
+	}
+	sort.Slice(seq, func(i, j int) bool { return seq[i] < seq[j] })
+	return seq
+}
+
+// Expect fails the test if any of capabilities is not supported.
+func (r Report) Expect(t *testing.T, caps ...Capability) {
+	t.Helper()
+	for _, c := range caps {
+		if !r[c] {
+			t.Errorf("capability %s is dropped by the provider", c)
+		}
+	}
+}
+
+func (r Report) String() string {
+	var sb strings.Builder
+	for _, c := range r.Supported() {
+		fmt.Fprintf(&sb, "  ✓ %s\n", c)
+	}
+	for _, c := range r.Dropped() {
+		fmt.Fprintf(&sb, "  ✗ %s (dropped)\n", c)
+	}
+	return sb.String()
+}
+
+//------------------------------------------------------------------------------
+
+// Run the conformance suite against the provider, the report of capabilities is logged.
+func Run[A, B any](t *testing.T, kit Kit[A, B]) Report {
+	t.Helper()
+
+	s := &suite[A, B]{kit: kit, report: Report{}}
+
+	t.Run("Stratum", s.stratum)
+	t.Run("MultiTurn", s.multiTurn)
+	t.Run("Tools", s.tools)
+	t.Run("Options", s.options)
+	t.Run("StopReasons", s.stopReasons)
+	t.Run("Reasoning", s.reasoning)
+	t.Run("CachePoint", s.cachePoint)
+
+	t.Logf("provider capabilities:\n%s", s.report)
+	return s.report
+}
+
+type suite[A, B any] struct {
+	kit    Kit[A, B]
+	report Report
+}
+
+// builds the request from the sequence of steps
+func (s *suite[A, B]) encode(t *testing.T, steps ...func(provider.Encoder[A]) error) Request {
+	t.Helper()
+
+	enc, err := s.kit.Factory()
+	if err != nil {
+		t.Fatalf("encoder factory has failed: %v", err)
+	}
+
+	for _, f := range steps {
+		if err := f(enc); err != nil {
+			t.Fatalf("encoder has failed: %v", err)
+		}
+	}
+
+	return s.kit.Inspect(enc.Build())
+}
+
+// decodes the response, the response is nil if it is not expressible by protocol
+func (s *suite[A, B]) decode(t *testing.T, r Response) *chatter.Reply {
+	t.Helper()
+
+	bag, err := s.kit.Response(r)
+	if err != nil {
+		return nil
+	}
+
+	reply, err := s.kit.Decoder.Decode(bag)
+	if err != nil {
+		t.Fatalf("decoder has failed: %v", err)
+	}
+
+	return reply
+}
+
+func (s *suite[A, B]) stratum(t *testing.T) {
+	req := s.encode(t,
+		func(e provider.Encoder[A]) error { return e.AsStratum("stratum-1") },
+		func(e provider.Encoder[A]) error { return e.AsText("text-1") },
+	)
+
+	inSystem := strings.Contains(req.System, "stratum-1")
+	inMessages := slices.ContainsFunc(req.Messages,
+		func(m Message) bool { return strings.Contains(m.Text, "stratum-1") },
+	)
+	s.report[Stratum] = inSystem || inMessages
+
+	if inMessages {
+		at := indexOf(req.Messages, "stratum-1")
+		if at > indexOf(req.Messages, "text-1") {
+			t.Errorf("stratum follows the user message")
+		}
+	}
+}
+
+func (s *suite[A, B]) multiTurn(t *testing.T) {
+	req := s.encode(t,
+		func(e provider.Encoder[A]) error { return e.AsText("text-1") },
+		func(e provider.Encoder[A]) error {
+			return e.AsReply(&chatter.Reply{
+				Stage:   chatter.LLM_RETURN,
+				Content: []chatter.Content{chatter.Text("reply-1")},
+			})
+		},
+		func(e provider.Encoder[A]) error {
+			return e.AsPrompt(&chatter.Prompt{Task: "prompt-2"})
+		},
+	)
+
+	text, reply, prompt := indexOf(req.Messages, "text-1"), indexOf(req.Messages, "reply-1"), indexOf(req.Messages, "prompt-2")
+
+	s.report[MultiTurn] = text != -1 && prompt != -1
+	s.report[ReplyReplay] = reply != -1
+
+	if text != -1 && prompt != -1 && text > prompt {
+		t.Errorf("order of user messages is not preserved")
+	}
+
+	if reply != -1 {
+		if req.Messages[reply].Role != "assistant" {
+			t.Errorf("reply is replayed as %s", req.Messages[reply].Role)
+		}
+		if (text != -1 && reply < text) || (prompt != -1 && reply > prompt) {
+			t.Errorf("order of reply is not preserved")
+		}
+	}
+}
+
+func (s *suite[A, B]) tools(t *testing.T) {
+	cmd := chatter.Cmd{
+		Cmd:    "tool-1",
+		About:  "test tool",
+		Schema: json.RawMessage(`{"type":"object","properties":{"a":{"type":"string"}}}`),
+	}
+	invoke := chatter.Invoke{
+		Cmd:  "tool-1",
+		Args: chatter.Json{ID: "call-1", Source: "tool-1", Value: json.RawMessage(`{"a":"b"}`)},
+	}
+
+	req := s.encode(t,
+		func(e provider.Encoder[A]) error { e.WithCommand(cmd); return nil },
+		func(e provider.Encoder[A]) error { return e.AsText("text-1") },
+		func(e provider.Encoder[A]) error {
+			return e.AsReply(&chatter.Reply{
+				Stage:   chatter.LLM_INVOKE,
+				Content: []chatter.Content{invoke},
+			})
+		},
+		func(e provider.Encoder[A]) error {
+			return e.AsAnswer(&chatter.Answer{
+				Yield: []chatter.Json{{ID: "call-1", Source: "tool-1", Value: json.RawMessage(`{"c":"d"}`)}},
+			})
+		},
+	)
+
+	s.report[Tools] = slices.Contains(req.Tools, "tool-1")
+
+	inv := slices.IndexFunc(req.Messages, func(m Message) bool { return slices.Contains(m.Invoke, "tool-1") })
+	yield := slices.IndexFunc(req.Messages, func(m Message) bool { return slices.Contains(m.Yield, "call-1") })
+	s.report[ToolInvokeReplay] = inv != -1
+	s.report[ToolAnswer] = yield != -1
+
+	if inv != -1 && yield != -1 && inv > yield {
+		t.Errorf("tool answer precedes the invocation")
+	}
+
+	reply := s.decode(t, Response{Stage: chatter.LLM_INVOKE, Invoke: []chatter.Invoke{invoke}})
+	s.report[ToolInvoke] = false
+	if reply == nil {
+		return
+	}
+
+	for _, c := range reply.Content {
+		if v, ok := c.(chatter.Invoke); ok && v.Cmd == "tool-1" {
+			s.report[ToolInvoke] = true
+
+			var args map[string]string
+			if err := json.Unmarshal(v.Args.Value, &args); err != nil || args["a"] != "b" {
+				t.Errorf("arguments of tool are not preserved: %s", v.Args.Value)
+			}
+			if v.Args.ID != "call-1" {
+				t.Errorf("identity of tool invocation is not preserved: %s", v.Args.ID)
+			}
+		}
+	}
+}
+
+func (s *suite[A, B]) options(t *testing.T) {
+	inf := provider.Inferrer{
+		Temperature:   0.5,
+		TopP:          0.75,
+		MaxTokens:     128,
+		StopSequences: []string{"stop-1"},
+	}
+
+	req := s.encode(t,
+		func(e provider.Encoder[A]) error { e.WithInferrer(inf); return nil },
+		func(e provider.Encoder[A]) error { return e.AsText("text-1") },
+	)
+
+	check := func(c Capability, isDefined, isEqual bool) {
+		s.report[c] = isDefined
+		if isDefined && !isEqual {
+			t.Errorf("option %s is not preserved", c)
+		}
+	}
+
+	check(Temperature, req.Inferrer.Temperature != 0, req.Inferrer.Temperature == inf.Temperature)
+	check(TopP, req.Inferrer.TopP != 0, req.Inferrer.TopP == inf.TopP)
+	check(MaxTokens, req.Inferrer.MaxTokens != 0, req.Inferrer.MaxTokens == inf.MaxTokens)
+	check(StopSequences, len(req.Inferrer.StopSequences) != 0, slices.Equal(req.Inferrer.StopSequences, inf.StopSequences))
+}
+
+func (s *suite[A, B]) stopReasons(t *testing.T) {
+	usage := chatter.Usage{InputTokens: 10, ReplyTokens: 20}
+
+	for c, stage := range map[Capability]chatter.Stage{
+		StopReturn:     chatter.LLM_RETURN,
+		StopIncomplete: chatter.LLM_INCOMPLETE,
+	} {
+		reply := s.decode(t, Response{Stage: stage, Text: "reply-1", Usage: usage})
+		s.report[c] = reply != nil && reply.Stage == stage
+
+		if reply != nil && reply.String() != "reply-1" {
+			t.Errorf("text of reply is not preserved: %s", reply.String())
+		}
+
+		if stage == chatter.LLM_RETURN && reply != nil {
+			s.report[Usage] = reply.Usage.InputTokens == usage.InputTokens && reply.Usage.ReplyTokens == usage.ReplyTokens
+		}
+	}
+
+	reply := s.decode(t, Response{
+		Stage: chatter.LLM_INVOKE,
+		Invoke: []chatter.Invoke{
+			{Cmd: "tool-1", Args: chatter.Json{ID: "call-1", Value: json.RawMessage(`{}`)}},
+		},
+	})
+	s.report[StopInvoke] = reply != nil && reply.Stage == chatter.LLM_INVOKE
+}
+
+func (s *suite[A, B]) reasoning(t *testing.T) {
+	thought := chatter.Reasoning{Text: "reasoning-1", Signature: "signature-1"}
+
+	req := s.encode(t,
+		func(e provider.Encoder[A]) error {
+			e.WithInferrer(provider.Inferrer{ReasoningBudget: 4096})
+			return nil
+		},
+		func(e provider.Encoder[A]) error { return e.AsText("text-1") },
+		func(e provider.Encoder[A]) error {
+			return e.AsReply(&chatter.Reply{
+				Stage:   chatter.LLM_RETURN,
+				Content: []chatter.Content{thought, chatter.Text("reply-1")},
+			})
+		},
+		func(e provider.Encoder[A]) error { return e.AsText("text-2") },
+	)
+
+	s.report[ReasoningOption] = req.Inferrer.ReasoningEffort != "" || req.Inferrer.ReasoningBudget != 0
+
+	at := slices.IndexFunc(req.Messages, func(m Message) bool {
+		return slices.ContainsFunc(m.Reasoning, func(r chatter.Reasoning) bool { return r.Text == thought.Text })
+	})
+	s.report[ReasoningReplay] = at != -1
+
+	if at != -1 {
+		if req.Messages[at].Role != "assistant" {
+			t.Errorf("reasoning is replayed as %s", req.Messages[at].Role)
+		}
+		for _, r := range req.Messages[at].Reasoning {
+			if r.Text == thought.Text && r.Signature != thought.Signature {
+				t.Errorf("signature of reasoning is not preserved: %s", r.Signature)
+			}
+		}
+	}
+
+	reply := s.decode(t, Response{
+		Stage:     chatter.LLM_RETURN,
+		Text:      "reply-1",
+		Reasoning: []chatter.Reasoning{thought},
+	})
+	s.report[Reasoning] = reply != nil && reply.Reasoning() == thought.Text
+
+	if reply != nil && reply.String() != "reply-1" {
+		t.Errorf("text of reply is not preserved: %s", reply.String())
+	}
+}
+
+func (s *suite[A, B]) cachePoint(t *testing.T) {
+	req := s.encode(t,
+		func(e provider.Encoder[A]) error { return e.AsStratum("stratum-1") },
+		func(e provider.Encoder[A]) error { return e.AsCachePoint(chatter.CachePoint{}) },
+		func(e provider.Encoder[A]) error { return e.AsText("text-1") },
+		func(e provider.Encoder[A]) error { return e.AsCachePoint(chatter.CachePoint{}) },
+	)
+
+	s.report[CachePoint] = req.CachePoints > 0
+
+	if req.CachePoints > 2 {
+		t.Errorf("unexpected number of cache checkpoints: %d", req.CachePoints)
+	}
+	if indexOf(req.Messages, "text-1") == -1 {
+		t.Errorf("cache checkpoint drops the message")
+	}
+}
+
+func indexOf(seq []Message, text string) int {
+	return slices.IndexFunc(seq, func(m Message) bool { return strings.Contains(m.Text, text) })
+}
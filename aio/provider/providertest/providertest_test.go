@@ -0,0 +1,33 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package providertest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter/aio/provider/providertest"
+)
+
+func TestReport(t *testing.T) {
+	report := providertest.Report{
+		providertest.Tools:     false,
+		providertest.Stratum:   true,
+		providertest.MultiTurn: true,
+	}
+
+	it.Then(t).Should(
+		it.Seq(report.Supported()).Equal(providertest.MultiTurn, providertest.Stratum),
+		it.Seq(report.Dropped()).Equal(providertest.Tools),
+		it.True(strings.Contains(report.String(), "✗ tools (dropped)")),
+	)
+
+	report.Expect(t, providertest.Stratum, providertest.MultiTurn)
+}
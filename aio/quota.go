@@ -11,64 +11,136 @@ package aio
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/tokenizer"
 )
 
-// Quoting strategy for LLM I/O
+// Quoting strategy for LLM I/O, safe for concurrent use.
+//
+// Each request reserves the estimated token cost (prompt size and reply quota)
+// under the lock before the call, so that concurrent requests observe
+// the reservation and cannot overshoot the quota. The reservation is replaced
+// with actual reply usage afterwards.
 type Quota struct {
 	chatter.Chatter
-	maxEpoch int
-	epoch    int
+	mu        sync.Mutex
+	maxEpoch  int
+	epoch     int
+	tokenizer chatter.Tokenizer
 
 	maxUsage chatter.Usage
 	usage    chatter.Usage
+	reserved chatter.Usage
 }
 
-var _ chatter.Chatter = (*Quota)(nil)
+var (
+	_ chatter.Chatter = (*Quota)(nil)
+	_ chatter.Wrapper = (*Quota)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (q *Quota) Unwrap() chatter.Chatter { return q.Chatter }
 
 func NewQuota(maxEpoch int, maxUsage chatter.Usage, chatter chatter.Chatter) *Quota {
 	return &Quota{
-		Chatter:  chatter,
-		maxEpoch: maxEpoch,
-		epoch:    0,
-		maxUsage: maxUsage,
+		Chatter:   chatter,
+		maxEpoch:  maxEpoch,
+		epoch:     0,
+		maxUsage:  maxUsage,
+		tokenizer: tokenizer.ForModel(""),
 	}
 }
 
+// Use tokenizer to estimate prompt size, heuristic is used by default.
+func (q *Quota) WithTokenizer(tokenizer chatter.Tokenizer) *Quota {
+	q.tokenizer = tokenizer
+	return q
+}
+
 func (q *Quota) ResetQuota() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	q.epoch = 0
-	q.usage.InputTokens = 0
-	q.usage.ReplyTokens = 0
+	q.usage = chatter.Usage{}
 }
 
 func (q *Quota) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	reserved := estimate(q.tokenizer, prompt, opts)
+	if err := q.acquire(reserved); err != nil {
+		return nil, err
+	}
+
+	reply, err := q.Chatter.Prompt(ctx, prompt, opts...)
+
+	q.mu.Lock()
+	q.reserved.InputTokens -= reserved.InputTokens
+	q.reserved.ReplyTokens -= reserved.ReplyTokens
+	if err == nil {
+		q.usage.Add(reply.Usage)
+	}
+	q.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// estimates token cost of the request as prompt size and reply quota.
+func estimate(tokenizer chatter.Tokenizer, prompt []chatter.Message, opts []chatter.Opt) chatter.Usage {
+	usage := chatter.Usage{
+		InputTokens: chatter.CountTokens(tokenizer, prompt),
+	}
+
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case chatter.MaxTokens:
+			usage.ReplyTokens += int(v)
+		}
+	}
+
+	return usage
+}
+
+// checks the quota, acquires the epoch and reserves tokens
+func (q *Quota) acquire(reserve chatter.Usage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	if q.maxEpoch > 0 {
 		if q.epoch >= q.maxEpoch {
-			return nil, fmt.Errorf("execution aborted, %d epoch is exceeded the quota", q.epoch)
+			return fmt.Errorf("execution aborted, %d epoch is exceeded the quota", q.epoch)
 		}
-		q.epoch++
 	}
 
 	if q.maxUsage.InputTokens > 0 {
-		if q.usage.InputTokens >= q.maxUsage.InputTokens {
-			return nil, fmt.Errorf("execution aborted, %d input tokens is exceeded the quota", q.usage.InputTokens)
+		if n := q.usage.InputTokens + q.reserved.InputTokens; n >= q.maxUsage.InputTokens {
+			return fmt.Errorf("execution aborted, %d input tokens is exceeded the quota", n)
 		}
 	}
 
 	if q.maxUsage.ReplyTokens > 0 {
-		if q.usage.ReplyTokens >= q.maxUsage.ReplyTokens {
-			return nil, fmt.Errorf("execution aborted, %d reply tokens is exceeded the quota", q.usage.InputTokens)
+		if n := q.usage.ReplyTokens + q.reserved.ReplyTokens; n >= q.maxUsage.ReplyTokens {
+			return fmt.Errorf("execution aborted, %d reply tokens is exceeded the quota", n)
 		}
 	}
 
-	reply, err := q.Chatter.Prompt(ctx, prompt, opts...)
-	if err != nil {
-		return nil, err
+	if q.maxUsage.ReasoningTokens > 0 {
+		if q.usage.ReasoningTokens >= q.maxUsage.ReasoningTokens {
+			return fmt.Errorf("execution aborted, %d reasoning tokens is exceeded the quota", q.usage.ReasoningTokens)
+		}
 	}
 
-	q.usage.InputTokens += reply.Usage.InputTokens
-	q.usage.ReplyTokens += reply.Usage.ReplyTokens
+	if q.maxEpoch > 0 {
+		q.epoch++
+	}
 
-	return reply, nil
+	q.reserved.InputTokens += reserve.InputTokens
+	q.reserved.ReplyTokens += reserve.ReplyTokens
+
+	return nil
 }
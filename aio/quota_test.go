@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+// mock that blocks every prompt until released
+type gated struct {
+	release chan struct{}
+	reply   *chatter.Reply
+}
+
+func (mock gated) Usage() chatter.Usage { return chatter.Usage{} }
+
+func (mock gated) Prompt(ctx context.Context, _ []chatter.Message, _ ...chatter.Opt) (*chatter.Reply, error) {
+	select {
+	case <-mock.release:
+		return mock.reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestQuota(t *testing.T) {
+	prompt := []chatter.Message{chatter.Text("Make me a test.")}
+
+	t.Run("Sequential", func(t *testing.T) {
+		api := aio.NewQuota(0, chatter.Usage{ReplyTokens: 2000}, mockTokensUsage(1000))
+
+		_, err1 := api.Prompt(context.Background(), prompt)
+		_, err2 := api.Prompt(context.Background(), prompt)
+		_, err3 := api.Prompt(context.Background(), prompt)
+
+		it.Then(t).Should(
+			it.Nil(err1),
+			it.Nil(err2),
+		).ShouldNot(
+			it.Nil(err3),
+		)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		llm := gated{
+			release: make(chan struct{}),
+			reply:   &chatter.Reply{Usage: chatter.Usage{ReplyTokens: 1000}},
+		}
+		api := aio.NewQuota(0, chatter.Usage{ReplyTokens: 2000}, llm).
+			WithTokenizer(words{})
+
+		var wg sync.WaitGroup
+		var failed atomic.Int32
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := api.Prompt(context.Background(), prompt, chatter.MaxTokens(1000)); err != nil {
+					failed.Add(1)
+				}
+			}()
+		}
+
+		// reservations of 2 requests exhaust the quota, others fail immediately
+		for failed.Load() < 6 {
+			runtime.Gosched()
+		}
+		close(llm.release)
+		wg.Wait()
+
+		it.Then(t).Should(
+			it.Equal(failed.Load(), 6),
+		)
+	})
+
+	t.Run("RefundOnError", func(t *testing.T) {
+		llm := faulty{err: context.Canceled}
+		api := aio.NewQuota(0, chatter.Usage{ReplyTokens: 1000}, llm)
+
+		_, err1 := api.Prompt(context.Background(), prompt, chatter.MaxTokens(1000))
+		_, err2 := api.Prompt(context.Background(), prompt, chatter.MaxTokens(1000))
+
+		it.Then(t).Should(
+			it.Equal(err1, context.Canceled),
+			it.Equal(err2, context.Canceled),
+		)
+	})
+}
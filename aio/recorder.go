@@ -0,0 +1,216 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kshard/chatter"
+)
+
+// Mode of the recorder
+type RecorderMode int
+
+const (
+	// Replays recorded replies, unknown requests are failed
+	ModeReplay RecorderMode = iota
+
+	// Calls LLM and records replies, existing cassettes are overwritten
+	ModeRecord
+
+	// Replays recorded replies, calls LLM and records unknown requests
+	ModeAuto
+)
+
+// ErrNoCassette is returned in replay mode if the request is not recorded.
+var ErrNoCassette = errors.New("cassette is not recorded")
+
+// Recorder of LLM I/O, it writes request/reply pairs into cassettes and replays
+// them offline, allowing deterministic tests of LLM workflows. Cassettes are
+// files in the directory, named by the canonical hash of the request (see [Cache]).
+// The model identity is not a part of the hash, so that cassettes are replayed
+// without the client. It is recorded in the cassette and verified on replay
+// if the model is known.
+//
+//	// record cassettes once with the real client
+//	llm := aio.NewRecorder(aio.ModeRecord, "testdata/cassettes", llm)
+//
+//	// replay them in CI
+//	llm := aio.NewRecorder(aio.ModeReplay, "testdata/cassettes", nil)
+type Recorder struct {
+	chatter.Chatter
+	mode  RecorderMode
+	dir   string
+	model string
+	salt  string
+	usage chatter.Meter
+}
+
+var (
+	_ chatter.Chatter = (*Recorder)(nil)
+	_ chatter.Wrapper = (*Recorder)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (r *Recorder) Unwrap() chatter.Chatter { return r.Chatter }
+
+// cassette is the recorded request/reply pair
+type cassette struct {
+	Version int             `json:"version"`
+	Model   string          `json:"model,omitempty"`
+	Request json.RawMessage `json:"request"`
+	Reply   json.RawMessage `json:"reply"`
+}
+
+const cassetteVersion = 1
+
+// Creates record/replay layer for LLM client, the client is not used in
+// the replay mode and might be nil.
+func NewRecorder(mode RecorderMode, dir string, chatter chatter.Chatter) *Recorder {
+	return &Recorder{
+		Chatter: chatter,
+		model:   modelID(chatter),
+		mode:    mode,
+		dir:     dir,
+	}
+}
+
+// Set the model identity, cassettes recorded by other models are rejected.
+func (r *Recorder) WithModel(model string) *Recorder {
+	r.model = model
+	return r
+}
+
+// Set the salt (e.g. test name), it is a part of the request hash.
+func (r *Recorder) WithSalt(salt string) *Recorder {
+	r.salt = salt
+	return r
+}
+
+// Usage returns the usage of recorded and replayed replies.
+func (r *Recorder) Usage() chatter.Usage { return r.usage.Usage() }
+
+func (r *Recorder) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	key, err := hashKey(r.salt, "", prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(r.dir, hex.EncodeToString(key)+".json")
+
+	if r.mode != ModeRecord {
+		reply, err := r.replay(file)
+		switch {
+		case err == nil:
+			r.usage.Add(reply.Usage)
+			return reply, nil
+		case !errors.Is(err, ErrNoCassette) || r.mode == ModeReplay:
+			return nil, err
+		}
+	}
+
+	if r.Chatter == nil {
+		return nil, fmt.Errorf("recorder has no llm client")
+	}
+
+	reply, err := r.Chatter.Prompt(ctx, prompt, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.record(file, prompt, reply); err != nil {
+		return nil, err
+	}
+
+	r.usage.Add(reply.Usage)
+	return reply, nil
+}
+
+func (r *Recorder) replay(file string) (*chatter.Reply, error) {
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoCassette, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", file, err)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d: %s", c.Version, file)
+	}
+	if r.model != "" && c.Model != "" && c.Model != r.model {
+		return nil, fmt.Errorf("cassette %s is recorded by %s, expected %s", file, c.Model, r.model)
+	}
+
+	msg, err := chatter.UnmarshalMessage(c.Reply)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", file, err)
+	}
+
+	reply, ok := msg.(*chatter.Reply)
+	if !ok {
+		return nil, fmt.Errorf("invalid cassette %s: reply is %T", file, msg)
+	}
+
+	return reply, nil
+}
+
+func (r *Recorder) record(file string, prompt []chatter.Message, reply *chatter.Reply) error {
+	req, err := chatter.MarshalMessages(prompt)
+	if err != nil {
+		return err
+	}
+
+	rep, err := chatter.MarshalMessage(reply)
+	if err != nil {
+		return err
+	}
+
+	// compact encoding preserves raw JSON of tools as-is
+	b, err := json.Marshal(cassette{
+		Version: cassetteVersion,
+		Model:   r.model,
+		Request: req,
+		Reply:   rep,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+
+	fd, err := os.CreateTemp(r.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fd.Name())
+
+	if _, err := fd.Write(b); err != nil {
+		fd.Close()
+		return err
+	}
+
+	if err := fd.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(fd.Name(), file)
+}
@@ -0,0 +1,101 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+func TestRecorder(t *testing.T) {
+	reply := &chatter.Reply{
+		Stage: chatter.LLM_INVOKE,
+		Usage: chatter.Usage{InputTokens: 10, ReplyTokens: 20},
+		Content: []chatter.Content{
+			chatter.Text("ok"),
+			chatter.Invoke{Cmd: "cmd", Args: chatter.Json{ID: "1", Value: json.RawMessage(`{"a":1}`)}},
+		},
+	}
+	prompt := []chatter.Message{chatter.Stratum("stratum"), chatter.Text("ping")}
+
+	t.Run("RecordReplay", func(t *testing.T) {
+		dir := t.TempDir()
+
+		llm := &recorder{reply: reply}
+		rec := aio.NewRecorder(aio.ModeRecord, dir, llm).WithModel("m")
+		_, err := rec.Prompt(context.Background(), prompt, chatter.Temperature(0.5))
+		it.Then(t).Must(it.Nil(err))
+
+		files, err := os.ReadDir(dir)
+		it.Then(t).Must(it.Nil(err))
+		it.Then(t).Should(it.Equal(len(files), 1))
+
+		play := aio.NewRecorder(aio.ModeReplay, dir, nil).WithModel("m")
+		val, err := play.Prompt(context.Background(), prompt, chatter.Temperature(0.5))
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equiv(val, reply),
+			it.Equal(play.Usage().ReplyTokens, 20),
+		)
+	})
+
+	t.Run("ModelID", func(t *testing.T) {
+		dir := t.TempDir()
+
+		llm := &model{id: "gpt-4o", recorder: &recorder{reply: reply}}
+		rec := aio.NewRecorder(aio.ModeRecord, dir, llm)
+		_, err := rec.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		play := aio.NewRecorder(aio.ModeReplay, dir, nil)
+		val, err := play.Prompt(context.Background(), prompt)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equiv(val, reply),
+		)
+
+		other := aio.NewRecorder(aio.ModeReplay, dir, nil).WithModel("claude-3-haiku")
+		_, err = other.Prompt(context.Background(), prompt)
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("gpt-4o"),
+		)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		play := aio.NewRecorder(aio.ModeReplay, t.TempDir(), nil)
+
+		_, err := play.Prompt(context.Background(), prompt)
+		it.Then(t).Should(
+			it.True(errors.Is(err, aio.ErrNoCassette)),
+		)
+	})
+
+	t.Run("Auto", func(t *testing.T) {
+		llm := &recorder{reply: reply}
+		rec := aio.NewRecorder(aio.ModeAuto, t.TempDir(), llm)
+
+		for i := 0; i < 3; i++ {
+			_, err := rec.Prompt(context.Background(), prompt)
+			it.Then(t).Must(it.Nil(err))
+		}
+
+		_, err := rec.Prompt(context.Background(), []chatter.Message{chatter.Text("pong")})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(llm.calls, 2),
+		)
+	})
+}
@@ -24,10 +24,13 @@ func (Route) ChatterOpt() {}
 type Router struct {
 	llms     map[string]chatter.Chatter
 	fallback chatter.Chatter
-	usage    chatter.Usage
+	usage    chatter.Meter
 }
 
-var _ chatter.Chatter = (*Router)(nil)
+var (
+	_ chatter.Chatter       = (*Router)(nil)
+	_ chatter.UsageResetter = (*Router)(nil)
+)
 
 // Creates LLMs pools instance
 func NewRouter(llms map[string]chatter.Chatter, fallback chatter.Chatter) *Router {
@@ -37,7 +40,8 @@ func NewRouter(llms map[string]chatter.Chatter, fallback chatter.Chatter) *Route
 	}
 }
 
-func (p *Router) Usage() chatter.Usage { return p.usage }
+func (p *Router) Usage() chatter.Usage      { return p.usage.Usage() }
+func (p *Router) ResetUsage() chatter.Usage { return p.usage.Reset() }
 
 func (p *Router) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
 	llm := p.fallback
@@ -56,8 +60,7 @@ func (p *Router) Prompt(ctx context.Context, prompt []chatter.Message, opts ...c
 		return reply, err
 	}
 
-	p.usage.InputTokens += reply.Usage.InputTokens
-	p.usage.ReplyTokens += reply.Usage.ReplyTokens
+	p.usage.Add(reply.Usage)
 
 	return reply, nil
 }
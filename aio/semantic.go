@@ -0,0 +1,291 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kshard/chatter"
+)
+
+// VectorStore abstracts nearest-neighbour search of cached replies.
+// Entries are partitioned by the scope, the search never crosses the scope.
+type VectorStore interface {
+	// Search returns the most similar value within the scope and its cosine
+	// similarity. It returns nil value if the scope is empty.
+	Search(scope []byte, vector []float32) ([]byte, float32, error)
+
+	// Insert the value with its vector into the scope.
+	Insert(scope []byte, vector []float32, val []byte) error
+}
+
+// Semantic caching strategy for LLMs I/O
+//
+// The cache embeds the last user message and looks up the most similar
+// question asked before. The stored reply is returned if cosine similarity
+// is above the threshold. The lookup is scoped by the rest of the request:
+// the history, options, model identity and salt, so that the same question
+// within different context is not matched.
+//
+// Only final replies ([chatter.LLM_RETURN]) to user input ([chatter.Text] or
+// [chatter.Prompt]) are cached, other requests bypass the cache. Cache hits
+// do not consume the model, they are marked as [chatter.Reply.Cached] and
+// report the usage of the original reply. The cache is bypassed if the
+// embedding model fails, its usage is reported by [SemanticCache.EmbeddingUsage].
+type SemanticCache struct {
+	chatter.Chatter
+	embedder  *Embedder
+	store     VectorStore
+	threshold float32
+	model     string
+	salt      string
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	embedding chatter.Meter
+}
+
+var (
+	_ chatter.Chatter = (*SemanticCache)(nil)
+	_ chatter.Wrapper = (*SemanticCache)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (c *SemanticCache) Unwrap() chatter.Chatter { return c.Chatter }
+
+// Default capacity of in-memory store of semantic cache
+const semanticCacheEntries = 1024
+
+// Creates semantic caching layer for LLM client, using embedding model and
+// similarity threshold (e.g. 0.95). Replies are stored in-memory by default,
+// keeping 1024 recently used entries.
+func NewSemanticCache(embedder *Embedder, threshold float32, chatter chatter.Chatter) *SemanticCache {
+	return &SemanticCache{
+		Chatter:   chatter,
+		model:     modelID(chatter),
+		embedder:  embedder,
+		store:     NewVectors(semanticCacheEntries),
+		threshold: threshold,
+	}
+}
+
+// Use vector store for cached replies.
+func (c *SemanticCache) WithStore(store VectorStore) *SemanticCache {
+	c.store = store
+	return c
+}
+
+// Set the model identity, it is a part of the cache scope.
+func (c *SemanticCache) WithModel(model string) *SemanticCache {
+	c.model = model
+	return c
+}
+
+// Set the salt (e.g. namespace or version), it is a part of the cache scope.
+func (c *SemanticCache) WithSalt(salt string) *SemanticCache {
+	c.salt = salt
+	return c
+}
+
+// Stats returns hit/miss statistics of the cache.
+func (c *SemanticCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// EmbeddingUsage returns the usage of the embedding model by the cache.
+func (c *SemanticCache) EmbeddingUsage() chatter.Usage { return c.embedding.Usage() }
+
+func (c *SemanticCache) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	if len(prompt) == 0 {
+		return nil, fmt.Errorf("bad request, empty prompt")
+	}
+
+	last := prompt[len(prompt)-1]
+	switch last.(type) {
+	case chatter.Text, *chatter.Prompt:
+	default:
+		return c.Chatter.Prompt(ctx, prompt, opts...)
+	}
+
+	scope, err := hashKey(c.salt, c.model, prompt[:len(prompt)-1], opts)
+	if err != nil {
+		slog.Warn("failed to derive cache scope, bypass cache", "err", err)
+		return c.Chatter.Prompt(ctx, prompt, opts...)
+	}
+
+	vector, tokens, err := c.embedder.Embedding(ctx, last.String())
+	if err != nil {
+		slog.Warn("failed to embed prompt, bypass cache", "err", err)
+		return c.Chatter.Prompt(ctx, prompt, opts...)
+	}
+	c.embedding.Add(chatter.Usage{InputTokens: tokens})
+
+	val, similarity, err := c.store.Search(scope, vector)
+	if err != nil {
+		slog.Warn("failed to search cache, bypass cache", "err", err)
+		return c.Chatter.Prompt(ctx, prompt, opts...)
+	}
+
+	if val != nil && similarity >= c.threshold {
+		msg, err := chatter.UnmarshalMessage(val)
+		if reply, ok := msg.(*chatter.Reply); err == nil && ok {
+			c.hits.Add(1)
+			reply.Cached = true
+			return reply, nil
+		}
+
+		slog.Warn("failed to decode cached LLM reply", "err", err)
+	}
+
+	c.misses.Add(1)
+
+	reply, err := c.Chatter.Prompt(ctx, prompt, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.Stage == chatter.LLM_RETURN {
+		bin, err := chatter.MarshalMessage(reply)
+		if err != nil {
+			slog.Warn("failed to encode LLM reply", "err", err)
+			return reply, nil
+		}
+
+		if err := c.store.Insert(scope, vector, bin); err != nil {
+			slog.Warn("failed to cache LLM reply", "err", err)
+		}
+	}
+
+	return reply, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Vectors is in-memory vector store, it uses exhaustive search.
+type Vectors struct {
+	mu         sync.Mutex
+	maxEntries int
+	clock      uint64
+	entries    []vectorEntry
+}
+
+var _ VectorStore = (*Vectors)(nil)
+
+type vectorEntry struct {
+	scope  []byte
+	vector []float32
+	val    []byte
+	used   uint64
+}
+
+// Creates in-memory vector store limited by number of entries, the least
+// recently used entries are evicted first. Zero disables the limit.
+func NewVectors(maxEntries int) *Vectors {
+	return &Vectors{maxEntries: maxEntries}
+}
+
+func (v *Vectors) Search(scope []byte, vector []float32) ([]byte, float32, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	query := normalize(vector)
+
+	best := -1
+	var similarity float32 = -1
+	for i, e := range v.entries {
+		if !bytes.Equal(e.scope, scope) || len(e.vector) != len(query) {
+			continue
+		}
+
+		if s := dot(e.vector, query); s > similarity {
+			best, similarity = i, s
+		}
+	}
+
+	if best == -1 {
+		return nil, 0, nil
+	}
+
+	v.clock++
+	v.entries[best].used = v.clock
+
+	return v.entries[best].val, similarity, nil
+}
+
+func (v *Vectors) Insert(scope []byte, vector []float32, val []byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.clock++
+	entry := vectorEntry{
+		scope:  append([]byte(nil), scope...),
+		vector: normalize(vector),
+		val:    append([]byte(nil), val...),
+		used:   v.clock,
+	}
+
+	if v.maxEntries <= 0 || len(v.entries) < v.maxEntries {
+		v.entries = append(v.entries, entry)
+		return nil
+	}
+
+	// evicts the least recently used entry
+	lru := 0
+	for i, e := range v.entries {
+		if e.used < v.entries[lru].used {
+			lru = i
+		}
+	}
+	v.entries[lru] = entry
+
+	return nil
+}
+
+// Len returns number of entries in the store.
+func (v *Vectors) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return len(v.entries)
+}
+
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	norm = math.Sqrt(norm)
+
+	out := make([]float32, len(v))
+	if norm == 0 {
+		return out
+	}
+
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+func dot(a, b []float32) float32 {
+	var s float32
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
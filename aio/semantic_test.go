@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+// mock embedding model, maps text to vectors
+type embeddings map[string]chatter.Vector
+
+func (embeddings) Usage() chatter.Usage { return chatter.Usage{} }
+
+func (e embeddings) Prompt(ctx context.Context, seq []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	return &chatter.Reply{
+		Stage:   chatter.LLM_RETURN,
+		Usage:   chatter.Usage{InputTokens: 1},
+		Content: []chatter.Content{e[seq[0].String()]},
+	}, nil
+}
+
+func TestSemanticCache(t *testing.T) {
+	ctx := context.Background()
+	embedder := aio.NewEmbedder(embeddings{
+		"What is the capital of Finland?":         {1.0, 0.0, 0.1},
+		"Which city is the capital of Finland?":   {1.0, 0.0, 0.15},
+		"What is the population of Finland?":      {0.1, 1.0, 0.0},
+		"Tell me the capital of Finland, please.": {0.5, 0.5, 0.5},
+	})
+
+	t.Run("Similar", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{
+			Stage:   chatter.LLM_RETURN,
+			Usage:   chatter.Usage{InputTokens: 10, ReplyTokens: 1},
+			Content: []chatter.Content{chatter.Text("Helsinki")},
+		}}
+		c := aio.NewSemanticCache(embedder, 0.95, llm)
+
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")})
+		reply, err := c.Prompt(ctx, []chatter.Message{chatter.Text("Which city is the capital of Finland?")})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "Helsinki"),
+			it.Equal(reply.Usage, chatter.Usage{InputTokens: 10, ReplyTokens: 1}),
+			it.True(reply.Cached),
+			it.Equal(c.EmbeddingUsage().InputTokens, 2),
+			it.Equal(llm.calls, 1),
+			it.Equal(c.Stats().Hits, 1),
+			it.Equal(c.Stats().Misses, 1),
+		)
+	})
+
+	t.Run("Threshold", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Helsinki")}}}
+		c := aio.NewSemanticCache(embedder, 0.95, llm)
+
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")})
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the population of Finland?")})
+		c.Prompt(ctx, []chatter.Message{chatter.Text("Tell me the capital of Finland, please.")})
+		it.Then(t).Should(
+			it.Equal(llm.calls, 3),
+		)
+	})
+
+	t.Run("Scope", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Helsinki")}}}
+		c := aio.NewSemanticCache(embedder, 0.95, llm)
+
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")})
+		c.Prompt(ctx, []chatter.Message{chatter.Stratum("Answer in French."), chatter.Text("What is the capital of Finland?")})
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")}, chatter.Temperature(0.1))
+		it.Then(t).Should(
+			it.Equal(llm.calls, 3),
+		)
+	})
+
+	t.Run("EmbeddingFailure", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_RETURN, Content: []chatter.Content{chatter.Text("Helsinki")}}}
+		c := aio.NewSemanticCache(aio.NewEmbedder(faulty{errors.New("failed")}), 0.95, llm)
+
+		reply, err := c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "Helsinki"),
+			it.Equal(llm.calls, 1),
+		)
+	})
+
+	t.Run("Bypass", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Stage: chatter.LLM_INVOKE}}
+		c := aio.NewSemanticCache(embedder, 0.95, llm)
+
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")})
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?")})
+		c.Prompt(ctx, []chatter.Message{chatter.Text("What is the capital of Finland?"), &chatter.Answer{}})
+		it.Then(t).Should(
+			it.Equal(llm.calls, 3),
+		)
+	})
+}
+
+func TestVectors(t *testing.T) {
+	store := aio.NewVectors(2)
+
+	store.Insert([]byte("a"), []float32{1, 0}, []byte("x"))
+	store.Insert([]byte("a"), []float32{0, 1}, []byte("y"))
+
+	val, similarity, err := store.Search([]byte("a"), []float32{0, 2})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(string(val), "y"),
+		it.Equal(similarity, 1.0),
+	)
+
+	val, _, err = store.Search([]byte("b"), []float32{0, 2})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(len(val), 0),
+	)
+
+	// "x" is evicted, "y" is recently used
+	store.Insert([]byte("a"), []float32{1, 1}, []byte("z"))
+	val, _, err = store.Search([]byte("a"), []float32{1, 0})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(store.Len(), 2),
+		it.Equal(string(val), "z"),
+	)
+
+	// "y" is evicted, "z" is recently used
+	store.Insert([]byte("a"), []float32{1, 0}, []byte("x"))
+	val, _, err = store.Search([]byte("a"), []float32{0, 1})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(store.Len(), 2),
+		it.Equal(string(val), "z"),
+	)
+}
@@ -0,0 +1,197 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/kshard/chatter"
+)
+
+// Redactor hook masks sensitive data in the logged text.
+type Redactor func(string) string
+
+// Structured logger of LLM I/O based on log/slog. It emits one record per call
+// with the prompt, reply, tools invoked by the model, usage, latency and error.
+// Successful calls are sampled, failures are always logged.
+//
+//	llm := aio.NewSlog(slog.Default(), llm).
+//		WithRequest(true).
+//		WithRedactor(func(s string) string { return email.ReplaceAllString(s, "***") }).
+//		WithSampling(0.1)
+type Slog struct {
+	chatter.Chatter
+	logger    *slog.Logger
+	level     slog.Level
+	model     string
+	request   bool
+	redactors []Redactor
+	sampling  float64
+}
+
+var (
+	_ chatter.Chatter = (*Slog)(nil)
+	_ chatter.Wrapper = (*Slog)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (s *Slog) Unwrap() chatter.Chatter { return s.Chatter }
+
+// Creates structured logger for LLM client, it logs every call at info level.
+func NewSlog(logger *slog.Logger, chatter chatter.Chatter) *Slog {
+	return &Slog{
+		Chatter:  chatter,
+		model:    modelID(chatter),
+		logger:   logger,
+		level:    slog.LevelInfo,
+		sampling: 1.0,
+	}
+}
+
+// Set the model identity, it is logged with every record.
+func (s *Slog) WithModel(model string) *Slog {
+	s.model = model
+	return s
+}
+
+// Set the level of successful calls, failures are logged at error level.
+func (s *Slog) WithLevel(level slog.Level) *Slog {
+	s.level = level
+	return s
+}
+
+// Enables logging of the full request, only the latest message is logged by default.
+func (s *Slog) WithRequest(enabled bool) *Slog {
+	s.request = enabled
+	return s
+}
+
+// Appends redaction hook, hooks are applied in order to the text of
+// every logged message.
+func (s *Slog) WithRedactor(redactor Redactor) *Slog {
+	s.redactors = append(s.redactors, redactor)
+	return s
+}
+
+// Set the fraction of successful calls being logged, within [0, 1].
+func (s *Slog) WithSampling(rate float64) *Slog {
+	s.sampling = rate
+	return s
+}
+
+func (s *Slog) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	t := time.Now()
+	reply, err := s.Chatter.Prompt(ctx, prompt, opts...)
+	latency := time.Since(t)
+
+	level := s.level
+	if err != nil {
+		level = slog.LevelError
+	}
+
+	if !s.logger.Enabled(ctx, level) {
+		return reply, err
+	}
+
+	if err == nil && s.sampling < 1.0 && rand.Float64() >= s.sampling {
+		return reply, err
+	}
+
+	attrs := make([]slog.Attr, 0, 8)
+	if s.model != "" {
+		attrs = append(attrs, slog.String("model", s.model))
+	}
+
+	switch {
+	case s.request:
+		seq := make([]logMessage, 0, len(prompt))
+		for _, msg := range prompt {
+			if m, ok := s.message(msg); ok {
+				seq = append(seq, m)
+			}
+		}
+		attrs = append(attrs, slog.Any("request", seq))
+	case len(prompt) > 0:
+		if m, ok := s.message(prompt[len(prompt)-1]); ok {
+			attrs = append(attrs, slog.Any("prompt", m))
+		}
+	}
+
+	attrs = append(attrs, slog.Duration("latency", latency))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs,
+			slog.String("stage", string(reply.Stage)),
+			slog.String("reply", s.redact(reply.String())),
+		)
+		if tools := s.tools(reply); len(tools) > 0 {
+			attrs = append(attrs, slog.Any("tools", tools))
+		}
+		attrs = append(attrs, slog.Any("usage", reply.Usage))
+	}
+
+	s.logger.LogAttrs(ctx, level, "llm prompt", attrs...)
+
+	return reply, err
+}
+
+// logged message
+type logMessage struct {
+	Role  string    `json:"role"`
+	Text  string    `json:"text"`
+	Tools []logTool `json:"tools,omitempty"`
+}
+
+// logged tool invocation
+type logTool struct {
+	Name string `json:"name"`
+	Args string `json:"args"`
+}
+
+func (s *Slog) message(msg chatter.Message) (logMessage, bool) {
+	switch v := msg.(type) {
+	case chatter.CachePoint:
+		return logMessage{}, false
+	case chatter.Stratum:
+		return logMessage{Role: "system", Text: s.redact(v.String())}, true
+	case *chatter.Reply:
+		return logMessage{Role: "assistant", Text: s.redact(v.String()), Tools: s.tools(v)}, true
+	case *chatter.Answer:
+		text := ""
+		for _, y := range v.Yield {
+			text += string(y.Value)
+		}
+		return logMessage{Role: "tool", Text: s.redact(text)}, true
+	default:
+		return logMessage{Role: "user", Text: s.redact(msg.String())}, true
+	}
+}
+
+// tools invoked by the model, arguments are redacted as the text
+func (s *Slog) tools(reply *chatter.Reply) []logTool {
+	var seq []logTool
+	for _, c := range reply.Content {
+		if inv, ok := c.(chatter.Invoke); ok {
+			seq = append(seq, logTool{Name: inv.Cmd, Args: s.redact(string(inv.Args.Value))})
+		}
+	}
+	return seq
+}
+
+func (s *Slog) redact(text string) string {
+	for _, f := range s.redactors {
+		text = f(text)
+	}
+	return text
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+func TestSlog(t *testing.T) {
+	reply := &chatter.Reply{
+		Stage:   chatter.LLM_RETURN,
+		Usage:   chatter.Usage{InputTokens: 10, ReplyTokens: 20},
+		Content: []chatter.Content{chatter.Text("mail to bob@example.com")},
+	}
+	prompt := []chatter.Message{
+		chatter.Stratum("You are assistant"),
+		chatter.Text("Who is bob@example.com?"),
+	}
+	redact := func(s string) string { return strings.ReplaceAll(s, "bob@example.com", "***") }
+
+	records := func(buf *bytes.Buffer) []map[string]any {
+		seq := []map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var rec map[string]any
+			if err := json.Unmarshal([]byte(line), &rec); err == nil {
+				seq = append(seq, rec)
+			}
+		}
+		return seq
+	}
+
+	t.Run("Prompt", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		llm := aio.NewSlog(slog.New(slog.NewJSONHandler(buf, nil)), mock{reply}).
+			WithModel("test-model").
+			WithRedactor(redact)
+
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		seq := records(buf)
+		it.Then(t).Must(it.Equal(len(seq), 1))
+		it.Then(t).Should(
+			it.Equal(seq[0]["level"].(string), "INFO"),
+			it.Equal(seq[0]["model"].(string), "test-model"),
+			it.Equal(seq[0]["stage"].(string), "return"),
+			it.Equal(seq[0]["reply"].(string), "mail to ***"),
+			it.Equal(seq[0]["prompt"].(map[string]any)["text"].(string), "Who is ***?"),
+			it.Equal(seq[0]["usage"].(map[string]any)["replyTokens"].(float64), 20.0),
+		)
+	})
+
+	t.Run("Invoke", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		llm := aio.NewSlog(slog.New(slog.NewJSONHandler(buf, nil)), mock{&chatter.Reply{
+			Stage: chatter.LLM_INVOKE,
+			Content: []chatter.Content{
+				chatter.Invoke{Cmd: "mail", Args: chatter.Json{ID: "1", Value: json.RawMessage(`{"to":"bob@example.com"}`)}},
+			},
+		}}).WithRedactor(redact)
+
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		seq := records(buf)
+		it.Then(t).Must(it.Equal(len(seq), 1))
+
+		tools := seq[0]["tools"].([]any)
+		it.Then(t).Must(it.Equal(len(tools), 1))
+		it.Then(t).Should(
+			it.Equal(seq[0]["stage"].(string), "invoke"),
+			it.Equal(tools[0].(map[string]any)["name"].(string), "mail"),
+			it.Equal(tools[0].(map[string]any)["args"].(string), `{"to":"***"}`),
+		)
+	})
+
+	t.Run("Request", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		llm := aio.NewSlog(slog.New(slog.NewJSONHandler(buf, nil)), mock{reply}).
+			WithRequest(true)
+
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		seq := records(buf)
+		it.Then(t).Must(it.Equal(len(seq), 1))
+
+		req := seq[0]["request"].([]any)
+		it.Then(t).Should(
+			it.Equal(len(req), 2),
+			it.Equal(req[0].(map[string]any)["role"].(string), "system"),
+			it.Equal(req[1].(map[string]any)["role"].(string), "user"),
+		)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		llm := aio.NewSlog(slog.New(slog.NewJSONHandler(buf, nil)), faulty{errors.New("failed")}).
+			WithSampling(0)
+
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Fail(func() error { return err }))
+
+		seq := records(buf)
+		it.Then(t).Must(it.Equal(len(seq), 1))
+		it.Then(t).Should(
+			it.Equal(seq[0]["level"].(string), "ERROR"),
+			it.Equal(seq[0]["error"].(string), "failed"),
+		)
+	})
+
+	t.Run("Sampling", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		llm := aio.NewSlog(slog.New(slog.NewJSONHandler(buf, nil)), mock{reply}).
+			WithSampling(0)
+
+		for i := 0; i < 10; i++ {
+			_, err := llm.Prompt(context.Background(), prompt)
+			it.Then(t).Must(it.Nil(err))
+		}
+
+		it.Then(t).Should(it.Equal(len(records(buf)), 0))
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		llm := aio.NewSlog(slog.New(slog.NewJSONHandler(buf, nil)), mock{reply}).
+			WithLevel(slog.LevelDebug)
+
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		it.Then(t).Should(it.Equal(len(records(buf)), 0))
+	})
+}
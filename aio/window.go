@@ -0,0 +1,202 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kshard/chatter"
+)
+
+// Context window strategy for LLMs I/O.
+//
+// The window keeps the conversation within the token budget. It always keeps
+// the [chatter.Stratum] and the latest turn. Older turns are dropped as whole,
+// the turn starts with user input ([chatter.Text] or [chatter.Prompt]) and
+// includes all replies and tool answers that follows, so that invocation and
+// its answer are never split. Optionally, dropped turns are replaced with
+// summary generated by LLM, the usage of summarizer is added to the reply.
+type Window struct {
+	chatter.Chatter
+	budget    int
+	tokenizer chatter.Tokenizer
+
+	summarizer    chatter.Chatter
+	summaryTokens int
+}
+
+var (
+	_ chatter.Chatter = (*Window)(nil)
+	_ chatter.Wrapper = (*Window)(nil)
+)
+
+// Unwrap returns the wrapped chatter
+func (w *Window) Unwrap() chatter.Chatter { return w.Chatter }
+
+// Creates context window strategy for LLMs with the given token budget.
+// The reply quota ([chatter.MaxTokens]) is deducted from the budget.
+func NewWindow(budget int, tokenizer chatter.Tokenizer, chatter chatter.Chatter) *Window {
+	return &Window{
+		Chatter:   chatter,
+		budget:    budget,
+		tokenizer: tokenizer,
+	}
+}
+
+// Replace dropped turns with summary generated by LLM. The summary is limited
+// to maxTokens, which are reserved from the budget.
+func (w *Window) WithSummary(maxTokens int, summarizer chatter.Chatter) *Window {
+	w.summarizer = summarizer
+	w.summaryTokens = maxTokens
+	return w
+}
+
+func (w *Window) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	budget := w.budget
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case chatter.MaxTokens:
+			budget -= int(v)
+		}
+	}
+
+	if chatter.CountTokens(w.tokenizer, prompt) <= budget {
+		return w.Chatter.Prompt(ctx, prompt, opts...)
+	}
+
+	stratum, turns := splitTurns(prompt)
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("context window exceeded, %d tokens budget", budget)
+	}
+
+	if w.summarizer != nil {
+		budget -= w.summaryTokens
+	}
+
+	used := chatter.CountTokens(w.tokenizer, stratum) +
+		chatter.CountTokens(w.tokenizer, turns[len(turns)-1])
+	if used > budget {
+		return nil, fmt.Errorf("context window exceeded, the latest turn requires %d tokens of %d budget", used, budget)
+	}
+
+	at := len(turns) - 1
+	for at > 0 {
+		n := chatter.CountTokens(w.tokenizer, turns[at-1])
+		if used+n > budget {
+			break
+		}
+		used += n
+		at--
+	}
+
+	seq := make([]chatter.Message, 0, len(prompt))
+	seq = append(seq, stratum...)
+
+	var usage chatter.Usage
+	if w.summarizer != nil && at > 0 {
+		summary, err := w.summarize(ctx, turns[:at])
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, chatter.Stratum("Summary of the earlier conversation:\n"+summary.String()))
+		usage = summary.Usage
+	}
+
+	for _, turn := range turns[at:] {
+		seq = append(seq, turn...)
+	}
+
+	reply, err := w.Chatter.Prompt(ctx, seq, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if usage != (chatter.Usage{}) {
+		// the reply might be shared by the underlying chatter
+		r := *reply
+		r.Usage.Add(usage)
+		reply = &r
+	}
+
+	return reply, nil
+}
+
+// summarize dropped turns into stratum
+func (w *Window) summarize(ctx context.Context, turns [][]chatter.Message) (*chatter.Reply, error) {
+	var transcript strings.Builder
+	for _, turn := range turns {
+		for _, msg := range turn {
+			transcribe(&transcript, msg)
+		}
+	}
+
+	var prompt chatter.Prompt
+	prompt.WithTask("Summarize the conversation between user and assistant.")
+	prompt.WithRules(
+		"Strictly adhere to the following requirements when generating a response.",
+		"Preserve facts, decisions, open questions and results of tool invocations.",
+		"Be concise, use third person.",
+		"Do not output any explanations, output the summary only.",
+	)
+	prompt.WithBlob("Conversation:", transcript.String())
+
+	return w.summarizer.Prompt(ctx, prompt.ToSeq(), chatter.MaxTokens(w.summaryTokens))
+}
+
+func transcribe(w *strings.Builder, msg chatter.Message) {
+	switch v := msg.(type) {
+	case *chatter.Reply:
+		for _, c := range v.Content {
+			switch x := c.(type) {
+			case chatter.Text:
+				fmt.Fprintf(w, "assistant: %s\n", x)
+			case chatter.Invoke:
+				fmt.Fprintf(w, "assistant: invoke %s %s\n", x.Cmd, x.Args.Value)
+			}
+		}
+	case *chatter.Answer:
+		for _, y := range v.Yield {
+			fmt.Fprintf(w, "tool %s: %s\n", y.Source, y.Value)
+		}
+	default:
+		fmt.Fprintf(w, "user: %s\n", msg.String())
+	}
+}
+
+// splits conversation into stratum and turns. The turn is started by the user
+// input and includes all replies and answers that follows.
+func splitTurns(seq []chatter.Message) ([]chatter.Message, [][]chatter.Message) {
+	stratum := make([]chatter.Message, 0)
+	turns := make([][]chatter.Message, 0)
+
+	for _, msg := range seq {
+		switch msg.(type) {
+		case chatter.Stratum:
+			stratum = append(stratum, msg)
+		case chatter.CachePoint:
+			// checkpoint follows the preceding message
+			if len(turns) == 0 {
+				stratum = append(stratum, msg)
+			} else {
+				turns[len(turns)-1] = append(turns[len(turns)-1], msg)
+			}
+		case *chatter.Reply, *chatter.Answer:
+			if len(turns) == 0 {
+				turns = append(turns, []chatter.Message{})
+			}
+			turns[len(turns)-1] = append(turns[len(turns)-1], msg)
+		default:
+			turns = append(turns, []chatter.Message{msg})
+		}
+	}
+
+	return stratum, turns
+}
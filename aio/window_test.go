@@ -0,0 +1,117 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package aio_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio"
+)
+
+// counts words as tokens
+type words struct{}
+
+func (words) CountTokens(s string) int { return len(strings.Fields(s)) }
+
+func TestWindow(t *testing.T) {
+	// Each message costs chatter.TokensPerMessage + number of words
+	conversation := []chatter.Message{
+		chatter.Stratum("act as assistant"),
+		chatter.Text("first question"),
+		&chatter.Reply{Content: []chatter.Content{chatter.Text("first answer")}},
+		chatter.Text("call the tool"),
+		&chatter.Reply{
+			Stage:   chatter.LLM_INVOKE,
+			Content: []chatter.Content{chatter.Invoke{Cmd: "tool", Args: chatter.Json{ID: "1", Value: []byte(`{}`)}}},
+		},
+		&chatter.Answer{Yield: []chatter.Json{{ID: "1", Source: "tool", Value: []byte(`{}`)}}},
+		&chatter.Reply{Content: []chatter.Content{chatter.Text("tool answer")}},
+		chatter.Text("last question"),
+	}
+
+	t.Run("PassThrough", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{}}
+		w := aio.NewWindow(1000, words{}, llm)
+
+		_, err := w.Prompt(context.Background(), conversation)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(llm.seq), len(conversation)),
+		)
+	})
+
+	t.Run("KeepsStratumAndLatestTurn", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{}}
+		w := aio.NewWindow(20, words{}, llm)
+
+		_, err := w.Prompt(context.Background(), conversation)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(llm.seq), 2),
+			it.Equal(llm.seq[0].String(), "act as assistant"),
+			it.Equal(llm.seq[1].String(), "last question"),
+		)
+	})
+
+	t.Run("NeverSplitsInvoke", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{}}
+		w := aio.NewWindow(40, words{}, llm)
+
+		_, err := w.Prompt(context.Background(), conversation)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(llm.seq), 6),
+			it.Equal(llm.seq[1].String(), "call the tool"),
+		)
+	})
+
+	t.Run("MaxTokens", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{}}
+		w := aio.NewWindow(60, words{}, llm)
+
+		_, err := w.Prompt(context.Background(), conversation, chatter.MaxTokens(20))
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(llm.seq), 6),
+		)
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{Usage: chatter.Usage{InputTokens: 40, ReplyTokens: 10}}}
+		summarizer := &recorder{reply: &chatter.Reply{
+			Content: []chatter.Content{chatter.Text("user asked a question")},
+			Usage:   chatter.Usage{InputTokens: 30, ReplyTokens: 5},
+		}}
+		w := aio.NewWindow(49, words{}, llm).WithSummary(10, summarizer)
+
+		reply, err := w.Prompt(context.Background(), conversation)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.Usage, chatter.Usage{InputTokens: 70, ReplyTokens: 15}),
+			it.Equal(llm.reply.Usage, chatter.Usage{InputTokens: 40, ReplyTokens: 10}),
+			it.Equal(len(llm.seq), 7),
+			it.Equal(llm.seq[0].String(), "act as assistant"),
+			it.Equal(llm.seq[1].String(), "Summary of the earlier conversation:\nuser asked a question"),
+			it.Equal(llm.seq[2].String(), "call the tool"),
+			it.String(summarizer.seq[0].String()).Contain("user: first question\nassistant: first answer"),
+		)
+	})
+
+	t.Run("Exceeded", func(t *testing.T) {
+		llm := &recorder{reply: &chatter.Reply{}}
+		w := aio.NewWindow(5, words{}, llm)
+
+		_, err := w.Prompt(context.Background(), conversation)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+}
@@ -11,6 +11,8 @@ package chatter
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 )
 
 type Opt = interface{ ChatterOpt() }
@@ -23,8 +25,111 @@ type Chatter interface {
 
 // LLM Usage stats
 type Usage struct {
+	// Total number of input tokens, including tokens read or written to cache
 	InputTokens int `json:"inputTokens"`
+
+	// Total number of output tokens, including reasoning tokens
 	ReplyTokens int `json:"replyTokens"`
+
+	// Input tokens read from prompt cache, part of InputTokens
+	CacheReadTokens int `json:"cacheReadTokens,omitempty"`
+
+	// Input tokens written to prompt cache, part of InputTokens
+	CacheWriteTokens int `json:"cacheWriteTokens,omitempty"`
+
+	// Output tokens used for reasoning (thinking), part of ReplyTokens
+	ReasoningTokens int `json:"reasoningTokens,omitempty"`
+}
+
+// Add accumulates usage stats
+func (u *Usage) Add(x Usage) {
+	u.InputTokens += x.InputTokens
+	u.ReplyTokens += x.ReplyTokens
+	u.CacheReadTokens += x.CacheReadTokens
+	u.CacheWriteTokens += x.CacheWriteTokens
+	u.ReasoningTokens += x.ReasoningTokens
+}
+
+// Chatter that supports snapshot-and-reset of usage stats, allowing
+// application to report usage per interval.
+type UsageResetter interface {
+	// Returns usage accumulated since the last reset and resets counters
+	ResetUsage() Usage
+}
+
+// Chatter that wraps another one, e.g. middleware. It allows application to
+// reach capabilities of the wrapped instance.
+type Wrapper interface {
+	// Returns the wrapped instance
+	Unwrap() Chatter
+}
+
+// ResetUsage returns usage accumulated since the last reset and resets
+// counters. Wrappers are unwrapped until the instance that supports
+// [UsageResetter], the error is returned if no such instance exists.
+func ResetUsage(llm Chatter) (Usage, error) {
+	for llm != nil {
+		if r, ok := llm.(UsageResetter); ok {
+			return r.ResetUsage(), nil
+		}
+
+		w, ok := llm.(Wrapper)
+		if !ok {
+			break
+		}
+		llm = w.Unwrap()
+	}
+
+	return Usage{}, fmt.Errorf("usage reset is not supported by %T", llm)
+}
+
+// ModelID returns identity of the model behind the chatter, as defined by
+// the vendor. Wrappers are unwrapped until the instance that implements
+// ModelID() string, empty string is returned if the identity is unknown.
+func ModelID(llm Chatter) string {
+	for llm != nil {
+		if id, ok := llm.(interface{ ModelID() string }); ok {
+			return id.ModelID()
+		}
+
+		w, ok := llm.(Wrapper)
+		if !ok {
+			break
+		}
+		llm = w.Unwrap()
+	}
+
+	return ""
+}
+
+// Meter is usage accumulator, safe for concurrent use.
+// The zero value is ready to use.
+type Meter struct {
+	mu    sync.Mutex
+	usage Usage
+}
+
+// Add usage stats to the meter
+func (m *Meter) Add(u Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage.Add(u)
+}
+
+// Usage returns snapshot of accumulated usage
+func (m *Meter) Usage() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+// Reset returns snapshot of accumulated usage and resets the meter
+func (m *Meter) Reset() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u := m.usage
+	m.usage = Usage{}
+	return u
 }
 
 // LLMs' critical parameter influencing the balance between predictability
@@ -60,6 +165,24 @@ type StopSequences []string
 
 func (StopSequences) ChatterOpt() {}
 
+// Reasoning effort of the model with extended thinking capabilities.
+// The effort is mapped to the token budget for providers that require it.
+type ReasoningEffort string
+
+const (
+	ReasoningLow    = ReasoningEffort("low")
+	ReasoningMedium = ReasoningEffort("medium")
+	ReasoningHigh   = ReasoningEffort("high")
+)
+
+func (ReasoningEffort) ChatterOpt() {}
+
+// Token budget for reasoning (thinking) of the model. The budget is mapped
+// to the effort for providers that do not support explicit budget.
+type ReasoningBudget int
+
+func (ReasoningBudget) ChatterOpt() {}
+
 // Command registry is a sequence of tools available for LLM usage.
 type Registry []Cmd
 
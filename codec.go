@@ -0,0 +1,345 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version of the wire format used by the codec.
+const CodecVersion = 1
+
+// The codec is tagged and versioned JSON encoding of [Message] and [Content].
+// Unlike default JSON encoding, each polymorphic value is wrapped into
+// the envelope with type discriminator, making the encoding lossless:
+//
+//	{"version": 1, "type": "text", "data": "Hello"}
+//
+// The provider specific message of [Invoke] is not portable. It is encoded
+// in provider-neutral form (command, id and arguments), which is replayed by
+// the providers as tool use.
+//
+// Providers reply with *[Binary] (e.g. generated images), the codec accepts it
+// but normalizes to [Binary] value, it is decoded as value.
+
+// envelope of polymorphic content and messages, type tag discriminates
+// the value for decoding.
+type envelope struct {
+	Version int             `json:"version,omitempty"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func seal(kind string, v any) (envelope, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return envelope{}, err
+	}
+	return envelope{Type: kind, Data: b}, nil
+}
+
+func unseal[T any](e envelope) (T, error) {
+	var v T
+	err := json.Unmarshal(e.Data, &v)
+	return v, err
+}
+
+func checkVersion(version int) error {
+	if version < 1 || version > CodecVersion {
+		return fmt.Errorf("unsupported codec version %d", version)
+	}
+	return nil
+}
+
+// wire format of message sequence
+type wireMessages struct {
+	Version  int        `json:"version"`
+	Messages []envelope `json:"messages"`
+}
+
+// MarshalMessages encodes the sequence of messages (e.g. conversation)
+// into tagged, versioned JSON.
+func MarshalMessages(seq []Message) ([]byte, error) {
+	messages, err := encodeMessages(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(wireMessages{Version: CodecVersion, Messages: messages})
+}
+
+// UnmarshalMessages decodes the sequence of messages encoded by [MarshalMessages].
+func UnmarshalMessages(b []byte) ([]Message, error) {
+	var wire wireMessages
+	if err := json.Unmarshal(b, &wire); err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(wire.Version); err != nil {
+		return nil, err
+	}
+
+	return decodeMessages(wire.Messages)
+}
+
+// MarshalMessage encodes message into tagged, versioned JSON.
+func MarshalMessage(msg Message) ([]byte, error) {
+	e, err := encodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Version = CodecVersion
+	return json.Marshal(e)
+}
+
+// UnmarshalMessage decodes message encoded by [MarshalMessage].
+func UnmarshalMessage(b []byte) (Message, error) {
+	var e envelope
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(e.Version); err != nil {
+		return nil, err
+	}
+
+	return decodeMessage(e)
+}
+
+// MarshalContent encodes content block into tagged, versioned JSON.
+func MarshalContent(c Content) ([]byte, error) {
+	e, err := encodeContent(c)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Version = CodecVersion
+	return json.Marshal(e)
+}
+
+// UnmarshalContent decodes content block encoded by [MarshalContent].
+func UnmarshalContent(b []byte) (Content, error) {
+	var e envelope
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(e.Version); err != nil {
+		return nil, err
+	}
+
+	return decodeContent(e)
+}
+
+// wire format of prompt
+type wirePrompt struct {
+	Task    Task       `json:"task,omitempty"`
+	Content []envelope `json:"content,omitempty"`
+}
+
+// wire format of reply
+type wireReply struct {
+	Stage   Stage      `json:"stage"`
+	Usage   Usage      `json:"usage"`
+	Content []envelope `json:"content,omitempty"`
+}
+
+// wire format of invoke, provider-neutral
+type wireInvoke struct {
+	Cmd  string `json:"name"`
+	Args Json   `json:"args"`
+}
+
+func encodeMessages(seq []Message) ([]envelope, error) {
+	out := make([]envelope, len(seq))
+	for i, msg := range seq {
+		e, err := encodeMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+func decodeMessages(seq []envelope) ([]Message, error) {
+	out := make([]Message, len(seq))
+	for i, e := range seq {
+		msg, err := decodeMessage(e)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = msg
+	}
+	return out, nil
+}
+
+func encodeMessage(msg Message) (envelope, error) {
+	switch v := msg.(type) {
+	case Stratum:
+		return seal("stratum", string(v))
+	case Text:
+		return seal("text", string(v))
+	case *Prompt:
+		content, err := encodeContents(v.Content)
+		if err != nil {
+			return envelope{}, err
+		}
+		return seal("prompt", wirePrompt{Task: v.Task, Content: content})
+	case *Reply:
+		content, err := encodeContents(v.Content)
+		if err != nil {
+			return envelope{}, err
+		}
+		return seal("reply", wireReply{Stage: v.Stage, Usage: v.Usage, Content: content})
+	case *Answer:
+		return seal("answer", v)
+	case CachePoint:
+		return seal("cachepoint", v)
+	default:
+		return envelope{}, fmt.Errorf("unsupported message type %T", msg)
+	}
+}
+
+func decodeMessage(e envelope) (Message, error) {
+	switch e.Type {
+	case "stratum":
+		v, err := unseal[string](e)
+		return Stratum(v), err
+	case "text":
+		v, err := unseal[string](e)
+		return Text(v), err
+	case "prompt":
+		v, err := unseal[wirePrompt](e)
+		if err != nil {
+			return nil, err
+		}
+		content, err := decodeContents(v.Content)
+		if err != nil {
+			return nil, err
+		}
+		return &Prompt{Task: v.Task, Content: content}, nil
+	case "reply":
+		v, err := unseal[wireReply](e)
+		if err != nil {
+			return nil, err
+		}
+		content, err := decodeContents(v.Content)
+		if err != nil {
+			return nil, err
+		}
+		return &Reply{Stage: v.Stage, Usage: v.Usage, Content: content}, nil
+	case "answer":
+		return unseal[*Answer](e)
+	case "cachepoint":
+		return unseal[CachePoint](e)
+	default:
+		return nil, fmt.Errorf("unsupported message type %s", e.Type)
+	}
+}
+
+func encodeContents(seq []Content) ([]envelope, error) {
+	out := make([]envelope, len(seq))
+	for i, c := range seq {
+		e, err := encodeContent(c)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+func decodeContents(seq []envelope) ([]Content, error) {
+	out := make([]Content, len(seq))
+	for i, e := range seq {
+		c, err := decodeContent(e)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = c
+	}
+	return out, nil
+}
+
+func encodeContent(c Content) (envelope, error) {
+	switch v := c.(type) {
+	case Text:
+		return seal("text", string(v))
+	case Json:
+		return seal("json", v)
+	case Guide:
+		return seal("guide", v)
+	case Rules:
+		return seal("rules", v)
+	case Feedback:
+		return seal("feedback", v)
+	case Example:
+		return seal("example", v)
+	case Context:
+		return seal("context", v)
+	case Input:
+		return seal("input", v)
+	case Blob:
+		return seal("blob", v)
+	case Invoke:
+		return seal("invoke", wireInvoke{Cmd: v.Cmd, Args: v.Args})
+	case Reasoning:
+		return seal("reasoning", v)
+	case Vector:
+		return seal("vector", []float32(v))
+	case Binary:
+		return seal("binary", v)
+	case *Binary:
+		if v == nil {
+			return envelope{}, fmt.Errorf("unsupported content nil %T", c)
+		}
+		return seal("binary", *v)
+	default:
+		return envelope{}, fmt.Errorf("unsupported content type %T", c)
+	}
+}
+
+func decodeContent(e envelope) (Content, error) {
+	switch e.Type {
+	case "text":
+		v, err := unseal[string](e)
+		return Text(v), err
+	case "json":
+		return unseal[Json](e)
+	case "guide":
+		return unseal[Guide](e)
+	case "rules":
+		return unseal[Rules](e)
+	case "feedback":
+		return unseal[Feedback](e)
+	case "example":
+		return unseal[Example](e)
+	case "context":
+		return unseal[Context](e)
+	case "input":
+		return unseal[Input](e)
+	case "blob":
+		return unseal[Blob](e)
+	case "invoke":
+		v, err := unseal[wireInvoke](e)
+		return Invoke{Cmd: v.Cmd, Args: v.Args}, err
+	case "reasoning":
+		return unseal[Reasoning](e)
+	case "vector":
+		v, err := unseal[[]float32](e)
+		return Vector(v), err
+	case "binary":
+		return unseal[Binary](e)
+	default:
+		return nil, fmt.Errorf("unsupported content type %s", e.Type)
+	}
+}
@@ -0,0 +1,127 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fogfish/it/v2"
+)
+
+func TestCodec(t *testing.T) {
+	content := []Content{
+		Text("text"),
+		Json{ID: "1", Source: "tool", Value: json.RawMessage(`{"a":1}`)},
+		Guide{Note: "note", Text: []string{"guide"}},
+		Rules{Note: "note", Text: []string{"rule"}},
+		Feedback{Note: "note", Text: []string{"feedback"}},
+		Example{Input: "input", Reply: "reply"},
+		Context{Note: "note", Text: []string{"context"}},
+		Input{Note: "note", Text: []string{"input"}},
+		Blob{Note: "note", Text: "blob"},
+		Invoke{Cmd: "cmd", Args: Json{ID: "1", Value: json.RawMessage(`{"a":1}`)}},
+		Reasoning{Text: "reasoning", Signature: "signature", Redacted: []byte{1, 2, 3}},
+		Vector{1.0, 2.0},
+		Binary{Name: "image", Type: "image/png", Data: []byte{1, 2, 3}},
+	}
+
+	t.Run("Content", func(t *testing.T) {
+		for _, c := range content {
+			b, err := MarshalContent(c)
+			it.Then(t).Must(it.Nil(err))
+
+			v, err := UnmarshalContent(b)
+			it.Then(t).Should(
+				it.Nil(err),
+				it.Equiv(v, c),
+			)
+		}
+	})
+
+	t.Run("BinaryPointer", func(t *testing.T) {
+		bin := &Binary{Name: "image", Type: "image/png", Data: []byte{1, 2, 3}}
+
+		b, err := MarshalContent(bin)
+		it.Then(t).Must(it.Nil(err))
+
+		v, err := UnmarshalContent(b)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equiv[Content](v, *bin),
+		)
+
+		_, err = MarshalContent((*Binary)(nil))
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("Messages", func(t *testing.T) {
+		seq := []Message{
+			Stratum("stratum"),
+			CachePoint{TTL: time.Hour},
+			Text("text"),
+			&Prompt{Task: "task", Content: content},
+			&Reply{
+				Stage:   LLM_INVOKE,
+				Usage:   Usage{InputTokens: 10, ReplyTokens: 20},
+				Content: content,
+			},
+			&Answer{Yield: []Json{{ID: "1", Source: "cmd", Value: json.RawMessage(`{"b":2}`)}}},
+		}
+
+		b, err := MarshalMessages(seq)
+		it.Then(t).Must(it.Nil(err))
+
+		v, err := UnmarshalMessages(b)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equiv(v, seq),
+		)
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		msg := &Reply{Stage: LLM_RETURN, Content: []Content{Text("text")}}
+
+		b, err := MarshalMessage(msg)
+		it.Then(t).Must(it.Nil(err))
+
+		v, err := UnmarshalMessage(b)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equiv(v, Message(msg)),
+		)
+	})
+
+	t.Run("InvokeNeutral", func(t *testing.T) {
+		c := Invoke{Cmd: "cmd", Args: Json{ID: "1"}, Message: struct{ Raw string }{"provider"}}
+
+		b, err := MarshalContent(c)
+		it.Then(t).Must(it.Nil(err))
+
+		v, err := UnmarshalContent(b)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equiv(v, Content(Invoke{Cmd: "cmd", Args: Json{ID: "1"}})),
+		)
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		_, err := UnmarshalMessages([]byte(`{"version":99,"messages":[]}`))
+		it.Then(t).ShouldNot(it.Nil(err))
+
+		_, err = UnmarshalContent([]byte(`{"type":"text","data":"text"}`))
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := UnmarshalContent([]byte(`{"version":1,"type":"unknown","data":null}`))
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+}
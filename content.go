@@ -216,6 +216,8 @@ type Invoke struct {
 
 	// Original LLM message that triggered the invocation, as defined by the providers API.
 	// The message is used to maintain the converstation history and context.
+	// The message is not persisted, providers replay invocation from the command
+	// and arguments if the message is not defined (e.g. history restored from storage).
 	Message any `json:"-"`
 }
 
@@ -224,6 +226,25 @@ func (inv Invoke) RawMessage() any { return inv.Message }
 
 //------------------------------------------------------------------------------
 
+// Reasoning is the thinking process of the model preceding the reply, generated
+// by models with extended thinking enabled (see [ReasoningBudget] and
+// [ReasoningEffort]). Providers require reasoning to be replayed unchanged
+// within the conversation, the signature verifies its integrity.
+type Reasoning struct {
+	// Text of the reasoning
+	Text string `json:"text,omitempty"`
+
+	// Opaque signature of the reasoning, as defined by the provider.
+	Signature string `json:"signature,omitempty"`
+
+	// Reasoning encrypted by the provider for safety reasons.
+	Redacted []byte `json:"redacted,omitempty"`
+}
+
+func (r Reasoning) String() string { return r.Text }
+
+//------------------------------------------------------------------------------
+
 // Vector is a sequence of float32 numbers representing the embedding vector.
 type Vector []float32
 
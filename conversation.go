@@ -0,0 +1,204 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Store abstracts the storage of conversations. It is compatible with
+// aio.KeyVal, any key-value implementation is usable to persist sessions.
+type Store interface {
+	Get([]byte) ([]byte, error)
+	Put([]byte, []byte) error
+}
+
+// Conversation is a session with LLM that owns the history of messages.
+// It appends each prompt, reply and answer to the history, tracking usage
+// per turn. Conversation is safe for concurrent use, turns are serialized.
+//
+//	conv := chatter.NewConversation(llm, chatter.Stratum("Act as translator."))
+//	reply, err := conv.Ask(ctx, chatter.Text("Translate hello to French."))
+//	for err == nil && reply.Stage == chatter.LLM_INVOKE {
+//		reply, err = conv.Invoke(ctx, tools)
+//	}
+type Conversation struct {
+	mu      sync.Mutex
+	llm     Chatter
+	history []Message
+	turns   []Usage
+}
+
+// Creates new conversation with LLM, optionally initialized by the history
+// (e.g. [Stratum]).
+func NewConversation(llm Chatter, history ...Message) *Conversation {
+	seq := make([]Message, len(history))
+	copy(seq, history)
+
+	return &Conversation{
+		llm:     llm,
+		history: seq,
+		turns:   make([]Usage, 0),
+	}
+}
+
+// Ask LLM within the conversation. The message and the reply are appended
+// to the history. The history is not changed if LLM fails.
+func (c *Conversation) Ask(ctx context.Context, msg Message, opts ...Opt) (*Reply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.prompt(ctx, msg, opts...)
+}
+
+// Invoke external tools requested by the latest reply, and returns results
+// to LLM within the conversation. The answer and the reply are appended to
+// the history. It fails if the latest reply does not require invocation.
+func (c *Conversation) Invoke(ctx context.Context, f func(string, json.RawMessage) (json.RawMessage, error), opts ...Opt) (*Reply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var last *Reply
+	if len(c.history) > 0 {
+		last, _ = c.history[len(c.history)-1].(*Reply)
+	}
+
+	if last == nil || last.Stage != LLM_INVOKE {
+		return nil, fmt.Errorf("bad request, the latest reply does not require invocation")
+	}
+
+	answer, err := last.Invoke(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.prompt(ctx, &answer, opts...)
+}
+
+func (c *Conversation) prompt(ctx context.Context, msg Message, opts ...Opt) (*Reply, error) {
+	seq := make([]Message, len(c.history), len(c.history)+2)
+	copy(seq, c.history)
+	seq = append(seq, msg)
+
+	reply, err := c.llm.Prompt(ctx, seq, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.history = append(seq, reply)
+	c.turns = append(c.turns, reply.Usage)
+
+	return reply, nil
+}
+
+// History returns copy of conversation messages.
+func (c *Conversation) History() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq := make([]Message, len(c.history))
+	copy(seq, c.history)
+	return seq
+}
+
+// Turns returns usage of each turn within the conversation.
+func (c *Conversation) Turns() []Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq := make([]Usage, len(c.turns))
+	copy(seq, c.turns)
+	return seq
+}
+
+// Usage returns total usage of the conversation.
+func (c *Conversation) Usage() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var total Usage
+	for _, u := range c.turns {
+		total.Add(u)
+	}
+	return total
+}
+
+// wire format of conversation
+type wireConversation struct {
+	Version int        `json:"version"`
+	History []envelope `json:"history"`
+	Turns   []Usage    `json:"turns,omitempty"`
+}
+
+func (c *Conversation) MarshalJSON() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	history, err := encodeMessages(c.history)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(wireConversation{Version: CodecVersion, History: history, Turns: c.turns})
+}
+
+func (c *Conversation) UnmarshalJSON(b []byte) error {
+	var wire wireConversation
+	if err := json.Unmarshal(b, &wire); err != nil {
+		return err
+	}
+
+	if err := checkVersion(wire.Version); err != nil {
+		return err
+	}
+
+	history, err := decodeMessages(wire.History)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.history = history
+	c.turns = wire.Turns
+	if c.turns == nil {
+		c.turns = make([]Usage, 0)
+	}
+
+	return nil
+}
+
+// Save conversation into the store under the key.
+func (c *Conversation) Save(store Store, key string) error {
+	b, err := c.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return store.Put([]byte(key), b)
+}
+
+// Load conversation from the store, resuming the session. The conversation
+// is not changed if the key is not found in the store.
+func (c *Conversation) Load(store Store, key string) error {
+	b, err := store.Get([]byte(key))
+	if err != nil {
+		return err
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	return c.UnmarshalJSON(b)
+}
@@ -0,0 +1,153 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+// mock LLM that replies sequence of messages
+type script struct {
+	seq     [][]Message
+	replies []*Reply
+}
+
+func (s *script) Usage() Usage { return Usage{} }
+
+func (s *script) Prompt(ctx context.Context, seq []Message, opts ...Opt) (*Reply, error) {
+	s.seq = append(s.seq, seq)
+	if len(s.replies) == 0 {
+		return nil, errors.New("no reply")
+	}
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	return reply, nil
+}
+
+// mock key-value store
+type keyval map[string][]byte
+
+func (kv keyval) Get(key []byte) ([]byte, error) { return kv[string(key)], nil }
+func (kv keyval) Put(key []byte, val []byte) error {
+	kv[string(key)] = val
+	return nil
+}
+
+func TestConversation(t *testing.T) {
+	invoke := &Reply{
+		Stage: LLM_INVOKE,
+		Usage: Usage{InputTokens: 10, ReplyTokens: 5},
+		Content: []Content{
+			Invoke{Cmd: "weather", Args: Json{ID: "1", Value: json.RawMessage(`{"city":"Helsinki"}`)}},
+		},
+	}
+	final := &Reply{
+		Stage:   LLM_RETURN,
+		Usage:   Usage{InputTokens: 20, ReplyTokens: 7},
+		Content: []Content{Text("It is sunny.")},
+	}
+
+	t.Run("AskAndInvoke", func(t *testing.T) {
+		llm := &script{replies: []*Reply{invoke, final}}
+		conv := NewConversation(llm, Stratum("Act as assistant."))
+
+		reply, err := conv.Ask(context.Background(), Text("What is the weather?"))
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.Stage, LLM_INVOKE),
+		)
+
+		reply, err = conv.Invoke(context.Background(),
+			func(cmd string, args json.RawMessage) (json.RawMessage, error) {
+				return json.RawMessage(`{"weather":"sunny"}`), nil
+			},
+		)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "It is sunny."),
+			it.Equal(len(conv.History()), 5),
+			it.Equal(len(llm.seq[1]), 4),
+			it.Seq(conv.Turns()).Equal(invoke.Usage, final.Usage),
+			it.Equal(conv.Usage().InputTokens, 30),
+			it.Equal(conv.Usage().ReplyTokens, 12),
+		)
+
+		answer := conv.History()[3].(*Answer)
+		it.Then(t).Should(
+			it.Equal(answer.Yield[0].ID, "1"),
+			it.Equal(string(answer.Yield[0].Value), `{"weather":"sunny"}`),
+		)
+	})
+
+	t.Run("InvokeWithoutRequest", func(t *testing.T) {
+		llm := &script{replies: []*Reply{final}}
+		conv := NewConversation(llm)
+
+		_, err := conv.Ask(context.Background(), Text("Hello"))
+		it.Then(t).Should(it.Nil(err))
+
+		_, err = conv.Invoke(context.Background(),
+			func(cmd string, args json.RawMessage) (json.RawMessage, error) { return nil, nil },
+		)
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("FailureKeepsHistory", func(t *testing.T) {
+		llm := &script{}
+		conv := NewConversation(llm, Stratum("Act as assistant."))
+
+		_, err := conv.Ask(context.Background(), Text("Hello"))
+		it.Then(t).Should(
+			it.Fail(func() error { return err }),
+			it.Equal(len(conv.History()), 1),
+		)
+	})
+
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		llm := &script{replies: []*Reply{invoke, final}}
+		conv := NewConversation(llm, Stratum("Act as assistant."))
+
+		var prompt Prompt
+		prompt.WithTask("What is the weather?").
+			WithRules("Follow requirements", "Be concise").
+			WithInput("City", "Helsinki")
+
+		conv.Ask(context.Background(), &prompt)
+		conv.Invoke(context.Background(),
+			func(cmd string, args json.RawMessage) (json.RawMessage, error) {
+				return json.RawMessage(`{"weather":"sunny"}`), nil
+			},
+		)
+
+		store := keyval{}
+		err := conv.Save(store, "session")
+		it.Then(t).Should(it.Nil(err))
+
+		resumed := NewConversation(llm)
+		err = resumed.Load(store, "session")
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(resumed.History()), 5),
+			it.Equiv(resumed.History(), conv.History()),
+			it.Seq(resumed.Turns()).Equal(invoke.Usage, final.Usage),
+		)
+
+		empty := NewConversation(llm, Stratum("Act as assistant."))
+		err = empty.Load(store, "unknown")
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(empty.History()), 1),
+		)
+	})
+}
@@ -5,5 +5,6 @@ go 1.25.0
 require (
 	github.com/fogfish/faults v0.3.2
 	github.com/fogfish/it/v2 v2.2.4
+	github.com/goccy/go-yaml v1.19.2
 	golang.org/x/time v0.15.0
 )
@@ -39,6 +39,9 @@ const (
 
 	// LLM has aborted execution due to error
 	LLM_ERROR = Stage("error")
+
+	// LLM reply is blocked by guardrails or content filters of the provider
+	LLM_GUARDRAIL = Stage("guardrail")
 )
 
 // The reply from LLMs
@@ -46,6 +49,10 @@ type Reply struct {
 	Stage   Stage     `json:"stage"`
 	Usage   Usage     `json:"usage"`
 	Content []Content `json:"content"`
+
+	// Reply is served from the cache, the usage is the one of the original
+	// reply, the model is not consumed.
+	Cached bool `json:"cached,omitempty"`
 }
 
 var _ Message = (*Reply)(nil)
@@ -63,6 +70,18 @@ func (reply Reply) String() string {
 	return strings.Join(seq, "")
 }
 
+// Reasoning of the model preceding the reply, if any.
+func (reply Reply) Reasoning() string {
+	seq := make([]string, 0)
+	for _, c := range reply.Content {
+		switch v := (c).(type) {
+		case Reasoning:
+			seq = append(seq, v.Text)
+		}
+	}
+	return strings.Join(seq, "")
+}
+
 // Helper function to invoke external tools
 func (reply Reply) Invoke(f func(string, json.RawMessage) (json.RawMessage, error)) (Answer, error) {
 	if reply.Stage != LLM_INVOKE {
@@ -0,0 +1,44 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+// Price of the model usage in USD per million tokens.
+// See github.com/kshard/chatter/pricing for built-in price table.
+type Price struct {
+	// Price of input tokens
+	Input float64 `json:"input" yaml:"input"`
+
+	// Price of output tokens
+	Reply float64 `json:"reply" yaml:"reply"`
+
+	// Price of input tokens read from prompt cache
+	CacheRead float64 `json:"cacheRead,omitempty" yaml:"cacheRead,omitempty"`
+
+	// Price of input tokens written to prompt cache
+	CacheWrite float64 `json:"cacheWrite,omitempty" yaml:"cacheWrite,omitempty"`
+}
+
+// Cost of the usage in USD. Cached tokens are charged at input price
+// if cache prices are not defined.
+func (p Price) Cost(u Usage) float64 {
+	cacheRead, cacheWrite := p.CacheRead, p.CacheWrite
+	if cacheRead == 0 {
+		cacheRead = p.Input
+	}
+	if cacheWrite == 0 {
+		cacheWrite = p.Input
+	}
+
+	input := u.InputTokens - u.CacheReadTokens - u.CacheWriteTokens
+
+	return (float64(input)*p.Input +
+		float64(u.CacheReadTokens)*cacheRead +
+		float64(u.CacheWriteTokens)*cacheWrite +
+		float64(u.ReplyTokens)*p.Reply) / 1e6
+}
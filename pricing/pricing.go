@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package pricing implements the price table of LLMs, used for cost accounting.
+//
+// The built-in table covers AWS Bedrock, OpenAI and Google Gemini models,
+// using public on-demand prices in USD per million tokens. Prices change,
+// applications override them through the configuration.
+package pricing
+
+import (
+	"strings"
+
+	"github.com/kshard/chatter"
+)
+
+// Table of prices, the key is model identifier or its prefix.
+type Table map[string]chatter.Price
+
+// Lookup the price of the model. The model identifier is matched against
+// the longest key prefix, so that versioned identifiers (e.g. gpt-4o-2024-08-06)
+// are resolved to the model family. The prefix ends at the boundary of
+// the identifier segment, the key "o1" does not match "o10". Variants priced
+// differently (e.g. o1-mini) require own keys. Cross-region inference
+// profiles of AWS Bedrock (e.g. us.anthropic.claude-...) are resolved to
+// the base model.
+func (t Table) Lookup(model string) (chatter.Price, bool) {
+	m := strings.ToLower(model)
+
+	// arn:aws:bedrock:region:account:inference-profile/us.amazon.nova-pro-v1:0
+	if i := strings.LastIndex(m, "/"); i != -1 {
+		m = m[i+1:]
+	}
+
+	if price, has := t[m]; has {
+		return price, true
+	}
+
+	for _, profile := range profiles {
+		if strings.HasPrefix(m, profile) {
+			m = m[len(profile):]
+			break
+		}
+	}
+
+	key := ""
+	for k := range t {
+		if len(k) > len(key) && strings.HasPrefix(m, k) && isBoundary(m, len(k)) {
+			key = k
+		}
+	}
+
+	if key == "" {
+		return chatter.Price{}, false
+	}
+
+	return t[key], true
+}
+
+// Lookup the price of the model in the built-in table.
+func Lookup(model string) (chatter.Price, bool) {
+	return Default.Lookup(model)
+}
+
+// checks that the prefix of identifier ends at the segment boundary
+func isBoundary(m string, at int) bool {
+	if at == len(m) {
+		return true
+	}
+
+	c := m[at]
+	return !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.')
+}
+
+// prefixes of AWS Bedrock cross-region inference profiles
+var profiles = []string{"us-gov.", "us.", "eu.", "apac.", "jp.", "au.", "ca.", "global."}
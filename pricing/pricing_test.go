@@ -0,0 +1,62 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package pricing_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/pricing"
+)
+
+func TestLookup(t *testing.T) {
+	for model, expect := range map[string]chatter.Price{
+		"gpt-4o":                 pricing.Default["gpt-4o"],
+		"gpt-4o-2024-08-06":      pricing.Default["gpt-4o"],
+		"gpt-4o-mini-2024-07-18": pricing.Default["gpt-4o-mini"],
+		"anthropic.claude-3-5-haiku-20241022-v1:0":                                         pricing.Default["anthropic.claude-3-5-haiku"],
+		"us.anthropic.claude-3-7-sonnet-20250219-v1:0":                                     pricing.Default["anthropic.claude-3-7-sonnet"],
+		"arn:aws:bedrock:eu-west-1:000000000000:inference-profile/eu.amazon.nova-pro-v1:0": pricing.Default["amazon.nova-pro-v1"],
+		"gemini-2.5-flash-lite":                                                            pricing.Default["gemini-2.5-flash-lite"],
+		"models/gemini-2.5-pro":                                                            pricing.Default["gemini-2.5-pro"],
+		"o1-2024-12-17":                                                                    pricing.Default["o1"],
+		"o1-mini-2024-09-12":                                                               pricing.Default["o1-mini"],
+		"o1-preview":                                                                       pricing.Default["o1-preview"],
+		"o3-pro":                                                                           pricing.Default["o3-pro"],
+	} {
+		price, ok := pricing.Lookup(model)
+		it.Then(t).Should(
+			it.True(ok),
+			it.Equal(price, expect),
+		)
+	}
+
+	_, ok := pricing.Lookup("unknown-model")
+	it.Then(t).ShouldNot(it.True(ok))
+
+	_, ok = pricing.Lookup("o10")
+	it.Then(t).ShouldNot(it.True(ok))
+}
+
+func TestCost(t *testing.T) {
+	price := chatter.Price{Input: 3.0, Reply: 15.0}
+	cost := price.Cost(chatter.Usage{InputTokens: 2000000, ReplyTokens: 100000})
+
+	it.Then(t).Should(it.Equal(cost, 7.5))
+
+	cached := chatter.Price{Input: 3.0, Reply: 15.0, CacheRead: 0.5, CacheWrite: 4.0}
+	cost = cached.Cost(chatter.Usage{InputTokens: 4000000, CacheReadTokens: 2000000, CacheWriteTokens: 1000000})
+
+	it.Then(t).Should(it.Equal(cost, 8.0))
+
+	cost = price.Cost(chatter.Usage{InputTokens: 4000000, CacheReadTokens: 2000000})
+
+	it.Then(t).Should(it.Equal(cost, 12.0))
+}
@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package pricing
+
+// Default is built-in price table, on-demand prices in USD per million tokens.
+var Default = Table{
+	//
+	// AWS Bedrock, Anthropic
+	"anthropic.claude-3-haiku":    {Input: 0.25, Reply: 1.25, CacheRead: 0.03, CacheWrite: 0.30},
+	"anthropic.claude-3-5-haiku":  {Input: 0.80, Reply: 4.00, CacheRead: 0.08, CacheWrite: 1.00},
+	"anthropic.claude-3-sonnet":   {Input: 3.00, Reply: 15.00},
+	"anthropic.claude-3-5-sonnet": {Input: 3.00, Reply: 15.00, CacheRead: 0.30, CacheWrite: 3.75},
+	"anthropic.claude-3-7-sonnet": {Input: 3.00, Reply: 15.00, CacheRead: 0.30, CacheWrite: 3.75},
+	"anthropic.claude-sonnet-4":   {Input: 3.00, Reply: 15.00, CacheRead: 0.30, CacheWrite: 3.75},
+	"anthropic.claude-3-opus":     {Input: 15.00, Reply: 75.00},
+	"anthropic.claude-opus-4":     {Input: 15.00, Reply: 75.00, CacheRead: 1.50, CacheWrite: 18.75},
+
+	//
+	// AWS Bedrock, Amazon
+	"amazon.nova-micro-v1":         {Input: 0.035, Reply: 0.14, CacheRead: 0.00875},
+	"amazon.nova-lite-v1":          {Input: 0.06, Reply: 0.24, CacheRead: 0.015},
+	"amazon.nova-pro-v1":           {Input: 0.80, Reply: 3.20, CacheRead: 0.20},
+	"amazon.nova-premier-v1":       {Input: 2.50, Reply: 12.50, CacheRead: 0.625},
+	"amazon.titan-embed-text-v1":   {Input: 0.10},
+	"amazon.titan-embed-text-v2":   {Input: 0.02},
+	"amazon.titan-embed-image-v1":  {Input: 0.80},
+	"amazon.titan-text-express-v1": {Input: 0.20, Reply: 0.60},
+
+	//
+	// AWS Bedrock, Meta
+	"meta.llama3-8b-instruct":    {Input: 0.30, Reply: 0.60},
+	"meta.llama3-70b-instruct":   {Input: 2.65, Reply: 3.50},
+	"meta.llama3-1-8b-instruct":  {Input: 0.22, Reply: 0.22},
+	"meta.llama3-1-70b-instruct": {Input: 0.72, Reply: 0.72},
+	"meta.llama3-2-1b-instruct":  {Input: 0.10, Reply: 0.10},
+	"meta.llama3-2-3b-instruct":  {Input: 0.15, Reply: 0.15},
+	"meta.llama3-2-11b-instruct": {Input: 0.16, Reply: 0.16},
+	"meta.llama3-2-90b-instruct": {Input: 0.72, Reply: 0.72},
+	"meta.llama3-3-70b-instruct": {Input: 0.72, Reply: 0.72},
+
+	//
+	// OpenAI
+	"gpt-4o":                 {Input: 2.50, Reply: 10.00, CacheRead: 1.25},
+	"gpt-4o-mini":            {Input: 0.15, Reply: 0.60, CacheRead: 0.075},
+	"gpt-4.1":                {Input: 2.00, Reply: 8.00, CacheRead: 0.50},
+	"gpt-4.1-mini":           {Input: 0.40, Reply: 1.60, CacheRead: 0.10},
+	"gpt-4.1-nano":           {Input: 0.10, Reply: 0.40, CacheRead: 0.025},
+	"gpt-5":                  {Input: 1.25, Reply: 10.00, CacheRead: 0.125},
+	"gpt-5-mini":             {Input: 0.25, Reply: 2.00, CacheRead: 0.025},
+	"gpt-5-nano":             {Input: 0.05, Reply: 0.40, CacheRead: 0.005},
+	"o1":                     {Input: 15.00, Reply: 60.00, CacheRead: 7.50},
+	"o1-mini":                {Input: 1.10, Reply: 4.40, CacheRead: 0.55},
+	"o1-preview":             {Input: 15.00, Reply: 60.00, CacheRead: 7.50},
+	"o1-pro":                 {Input: 150.00, Reply: 600.00},
+	"o3":                     {Input: 2.00, Reply: 8.00, CacheRead: 0.50},
+	"o3-mini":                {Input: 1.10, Reply: 4.40, CacheRead: 0.55},
+	"o3-pro":                 {Input: 20.00, Reply: 80.00},
+	"o4-mini":                {Input: 1.10, Reply: 4.40, CacheRead: 0.275},
+	"text-embedding-3-small": {Input: 0.02},
+	"text-embedding-3-large": {Input: 0.13},
+	"text-embedding-ada-002": {Input: 0.10},
+
+	//
+	// Google Gemini, prompts up to 200K tokens
+	"gemini-1.5-flash":      {Input: 0.075, Reply: 0.30, CacheRead: 0.01875},
+	"gemini-1.5-pro":        {Input: 1.25, Reply: 5.00, CacheRead: 0.3125},
+	"gemini-2.0-flash":      {Input: 0.10, Reply: 0.40, CacheRead: 0.025},
+	"gemini-2.0-flash-lite": {Input: 0.075, Reply: 0.30},
+	"gemini-2.5-flash":      {Input: 0.30, Reply: 2.50, CacheRead: 0.075},
+	"gemini-2.5-flash-lite": {Input: 0.10, Reply: 0.40, CacheRead: 0.025},
+	"gemini-2.5-pro":        {Input: 1.25, Reply: 10.00, CacheRead: 0.31},
+}
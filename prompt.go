@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // Ground level constrain of the model behavior.
@@ -29,6 +30,35 @@ func (s Stratum) String() string { return string(s) }
 
 //------------------------------------------------------------------------------
 
+// CachePoint marks the end of the reusable prefix of the conversation
+// (e.g. system prompt, tools catalog or large documents), allowing providers
+// to cache the prefix and reuse it across calls. The checkpoint covers
+// everything that precedes it, following the order of prompt evaluation:
+// tools, system and messages. Used as first message, it caches the tools;
+// after [Stratum], it caches the system prompt; otherwise, it caches
+// the conversation up to the checkpoint.
+//
+//	[]chatter.Message{
+//		chatter.Stratum("Act as ..."),
+//		chatter.CachePoint{},
+//		chatter.Text("..."),
+//	}
+//
+// Providers that cache prompts automatically or do not support caching
+// ignore the checkpoint. Tokens read from the cache are reported by
+// [Usage].CacheReadTokens.
+type CachePoint struct {
+	// Optional time-to-live of the cache, provider's default is used if not defined.
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// CachePoint is LLM Message
+func (CachePoint) HKT1(Message) {}
+
+func (CachePoint) String() string { return "" }
+
+//------------------------------------------------------------------------------
+
 // Prompt standardizes taxonomy of prompts for LLMs to solve complex tasks.
 // See https://aclanthology.org/2023.findings-emnlp.946.pdf
 //
@@ -0,0 +1,380 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package prompts implements the library of prompts, defined in YAML or JSON
+// files. It allows managing prompts like configuration, separately from
+// the Go code. Prompts are addressable by name and version, they are
+// [chatter.Template] and rendered as [chatter.Prompt].
+//
+//	name: translate
+//	version: 2
+//	task: Translate the text to {{.Lang}}
+//	rules:
+//	  note: Strictly adhere to the following requirements
+//	  text:
+//	    - Use {{.Tone}} tone
+//	examples:
+//	  - input: Hello
+//	    reply: Hei
+//	input:
+//	  note: Text to translate
+//	  text:
+//	    - "{{.Text}}"
+//
+// Use go:embed to ship the library within the application:
+//
+//	//go:embed prompts
+//	var fsys embed.FS
+//
+//	lib, err := prompts.Load(fsys)
+//	prompt, err := lib.Render("translate", "", vars)
+package prompts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+	"github.com/kshard/chatter"
+)
+
+// ErrNotFound is returned if the prompt is not defined in the library.
+var ErrNotFound = errors.New("prompt is not found")
+
+// Section of the prompt, e.g. guide, rules, feedback, context or input.
+type Section struct {
+	Note string   `json:"note,omitempty" yaml:"note,omitempty"`
+	Text []string `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// Blob of unformatted input data, the text is passed to the prompt as-is.
+type Blob struct {
+	Note string `json:"note,omitempty" yaml:"note,omitempty"`
+	Text string `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// Example of input data and expected outcomes.
+type Example struct {
+	Input string `json:"input" yaml:"input"`
+	Reply string `json:"reply" yaml:"reply"`
+}
+
+// Definition of the prompt as defined in the file.
+type Definition struct {
+	// [Required] Name of the prompt.
+	Name string `json:"name" yaml:"name"`
+
+	// Version of the prompt, e.g. "1", "1.2" or "2025-05-01".
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Free-form description of the prompt for its authors.
+	About string `json:"about,omitempty" yaml:"about,omitempty"`
+
+	Task     string    `json:"task,omitempty" yaml:"task,omitempty"`
+	Guide    *Section  `json:"guide,omitempty" yaml:"guide,omitempty"`
+	Rules    *Section  `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Feedback *Section  `json:"feedback,omitempty" yaml:"feedback,omitempty"`
+	Examples []Example `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Context  *Section  `json:"context,omitempty" yaml:"context,omitempty"`
+	Input    *Section  `json:"input,omitempty" yaml:"input,omitempty"`
+	Blob     *Blob     `json:"blob,omitempty" yaml:"blob,omitempty"`
+}
+
+// Template builds the template of the prompt from the definition.
+func (def Definition) Template() (*chatter.Template, error) {
+	tmpl := chatter.NewTemplate()
+
+	if def.Task != "" {
+		tmpl.WithTask(def.Task)
+	}
+	if def.Guide != nil {
+		tmpl.WithGuide(def.Guide.Note, def.Guide.Text...)
+	}
+	if def.Rules != nil {
+		tmpl.WithRules(def.Rules.Note, def.Rules.Text...)
+	}
+	if def.Feedback != nil {
+		tmpl.WithFeedback(def.Feedback.Note, def.Feedback.Text...)
+	}
+	for _, e := range def.Examples {
+		tmpl.WithExample(e.Input, e.Reply)
+	}
+	if def.Context != nil {
+		tmpl.WithContext(def.Context.Note, def.Context.Text...)
+	}
+	if def.Input != nil {
+		tmpl.WithInput(def.Input.Note, def.Input.Text...)
+	}
+	if def.Blob != nil {
+		tmpl.WithBlob(def.Blob.Note, def.Blob.Text)
+	}
+
+	if err := tmpl.Err(); err != nil {
+		return nil, fmt.Errorf("prompt %s@%s: %w", def.Name, def.Version, err)
+	}
+
+	return tmpl, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Library of prompts
+type Library struct {
+	mu      sync.RWMutex
+	entries map[string][]entry
+}
+
+type entry struct {
+	def  Definition
+	tmpl *chatter.Template
+}
+
+func New() *Library {
+	return &Library{entries: make(map[string][]entry)}
+}
+
+// Load the library from files *.yaml, *.yml and *.json of the file system.
+func Load(fsys fs.FS) (*Library, error) {
+	lib := New()
+	if err := lib.Load(fsys); err != nil {
+		return nil, err
+	}
+	return lib, nil
+}
+
+// Add prompt definitions to the library, the name and version must be unique.
+// Definitions are added all at once, the library is unchanged on error.
+func (lib *Library) Add(defs ...Definition) error {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	batch := make([]entry, 0, len(defs))
+	for _, def := range defs {
+		if def.Name == "" {
+			return fmt.Errorf("prompt name is not defined")
+		}
+
+		if lib.defined(batch, def) {
+			return fmt.Errorf("prompt %s@%s is already defined", def.Name, def.Version)
+		}
+
+		tmpl, err := def.Template()
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, entry{def: def, tmpl: tmpl})
+	}
+
+	for _, e := range batch {
+		seq := append(lib.entries[e.def.Name], e)
+		sort.SliceStable(seq, func(i, j int) bool {
+			return compareVersion(seq[i].def.Version, seq[j].def.Version) < 0
+		})
+		lib.entries[e.def.Name] = seq
+	}
+
+	return nil
+}
+
+// checks if the definition is either in the library or in the pending batch
+func (lib *Library) defined(batch []entry, def Definition) bool {
+	for _, seq := range [][]entry{lib.entries[def.Name], batch} {
+		for _, e := range seq {
+			if e.def.Name == def.Name && e.def.Version == def.Version {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Parse definitions from YAML or JSON, the input might contain multiple
+// YAML documents separated by "---". The library is unchanged on error.
+func (lib *Library) Parse(data []byte) error {
+	defs, err := parse(data)
+	if err != nil {
+		return err
+	}
+
+	return lib.Add(defs...)
+}
+
+func parse(data []byte) ([]Definition, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data), yaml.DisallowUnknownField())
+
+	var defs []Definition
+	for {
+		var def Definition
+		err := dec.Decode(&def)
+		if errors.Is(err, io.EOF) {
+			return defs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+}
+
+// Load definitions from files *.yaml, *.yml and *.json of the file system.
+// The library is unchanged if any of the files is invalid.
+func (lib *Library) Load(fsys fs.FS) error {
+	var defs []Definition
+	err := fs.WalkDir(fsys, ".", func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		switch path.Ext(file) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		seq, err := parse(data)
+		if err != nil {
+			return fmt.Errorf("invalid prompt file %s: %w", file, err)
+		}
+		defs = append(defs, seq...)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return lib.Add(defs...)
+}
+
+// Names of prompts defined in the library.
+func (lib *Library) Names() []string {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+
+	seq := make([]string, 0, len(lib.entries))
+	for name := range lib.entries {
+		seq = append(seq, name)
+	}
+	sort.Strings(seq)
+	return seq
+}
+
+// Versions of the prompt, in ascending order.
+func (lib *Library) Versions(name string) []string {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+
+	seq := make([]string, 0, len(lib.entries[name]))
+	for _, e := range lib.entries[name] {
+		seq = append(seq, e.def.Version)
+	}
+	return seq
+}
+
+// Definition of the prompt, empty version is the latest one.
+func (lib *Library) Definition(name, version string) (Definition, error) {
+	e, err := lib.lookup(name, version)
+	if err != nil {
+		return Definition{}, err
+	}
+	return e.def, nil
+}
+
+// Template of the prompt, empty version is the latest one.
+func (lib *Library) Template(name, version string) (*chatter.Template, error) {
+	e, err := lib.lookup(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return e.tmpl, nil
+}
+
+// Render the prompt with values of variables, empty version is the latest one.
+func (lib *Library) Render(name, version string, vars any) (*chatter.Prompt, error) {
+	e, err := lib.lookup(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := e.tmpl.Render(vars)
+	if err != nil {
+		return nil, fmt.Errorf("prompt %s@%s: %w", e.def.Name, e.def.Version, err)
+	}
+
+	return prompt, nil
+}
+
+// Prompt without variables, empty version is the latest one.
+func (lib *Library) Prompt(name, version string) (*chatter.Prompt, error) {
+	return lib.Render(name, version, nil)
+}
+
+func (lib *Library) lookup(name, version string) (entry, error) {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+
+	seq := lib.entries[name]
+	if len(seq) == 0 {
+		return entry{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if version == "" {
+		return seq[len(seq)-1], nil
+	}
+
+	for _, e := range seq {
+		if e.def.Version == version {
+			return e, nil
+		}
+	}
+
+	return entry{}, fmt.Errorf("%w: %s@%s", ErrNotFound, name, version)
+}
+
+// compares versions segment by segment, numeric segments are compared as numbers
+func compareVersion(a, b string) int {
+	sa := strings.FieldsFunc(strings.TrimPrefix(a, "v"), isVersionSeparator)
+	sb := strings.FieldsFunc(strings.TrimPrefix(b, "v"), isVersionSeparator)
+
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		na, errA := strconv.Atoi(sa[i])
+		nb, errB := strconv.Atoi(sb[i])
+
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			if na < nb {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && sa[i] != sb[i]:
+			return strings.Compare(sa[i], sb[i])
+		}
+	}
+
+	return len(sa) - len(sb)
+}
+
+func isVersionSeparator(r rune) bool { return r == '.' || r == '-' }
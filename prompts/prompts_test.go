@@ -0,0 +1,173 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package prompts_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/prompts"
+)
+
+var fsys = fstest.MapFS{
+	"translate/v1.yaml": {Data: []byte(`
+name: translate
+version: 1
+task: Translate the text to Finnish
+input:
+  note: Text to translate
+  text:
+    - Hello World
+`)},
+	"translate/v10.yml": {Data: []byte(`
+name: translate
+version: "10"
+task: Translate the text to {{.Lang}}
+rules:
+  note: Strictly adhere to the following requirements
+  text:
+    - Use {{.Tone}} tone
+examples:
+  - input: Hello
+    reply: Hei
+input:
+  note: Text to translate
+  text:
+    - "{{.Text}}"
+---
+name: translate
+version: "2"
+task: Translate the text
+`)},
+	"summarize.json": {Data: []byte(`{
+  "name": "summarize",
+  "version": "1.0",
+  "task": "Summarize the text",
+  "context": {"note": "The text is", "text": ["a formal letter"]}
+}`)},
+	"README.md": {Data: []byte(`# prompts`)},
+}
+
+func TestLibrary(t *testing.T) {
+	lib, err := prompts.Load(fsys)
+	it.Then(t).Must(it.Nil(err))
+
+	t.Run("Names", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(lib.Names()).Equal("summarize", "translate"),
+			it.Seq(lib.Versions("translate")).Equal("1", "2", "10"),
+		)
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		prompt, err := lib.Prompt("translate", "1")
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(prompt.String(), "Translate the text to Finnish.\nText to translate:\n- Hello World."),
+		)
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		prompt, err := lib.Render("translate", "", map[string]string{
+			"Lang": "Finnish", "Tone": "formal", "Text": "Hello World",
+		})
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(prompt.Task), "Translate the text to Finnish."),
+			it.Seq(prompt.Content).Equal(
+				chatter.Rules{
+					Note: "Strictly adhere to the following requirements:",
+					Text: []string{"Use formal tone."},
+				},
+				chatter.Example{Input: "Hello", Reply: "Hei"},
+				chatter.Input{Note: "Text to translate:", Text: []string{"Hello World"}},
+			),
+		)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		prompt, err := lib.Prompt("summarize", "")
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(prompt.String(), "Summarize the text.\nThe text is:\n- a formal letter."),
+		)
+	})
+
+	t.Run("Blob", func(t *testing.T) {
+		lib := prompts.New()
+		err := lib.Parse([]byte("name: review\ntask: Review the document\nblob:\n  note: The document is\n  text: \"{{.Doc}}\""))
+		it.Then(t).Must(it.Nil(err))
+
+		prompt, err := lib.Render("review", "", map[string]string{"Doc": "# Title"})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Seq(prompt.Content).Equal(
+				chatter.Blob{Note: "The document is:", Text: "# Title"},
+			),
+		)
+	})
+
+	t.Run("MissingVars", func(t *testing.T) {
+		_, err := lib.Render("translate", "10", map[string]string{"Lang": "Finnish"})
+
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("translate@10"),
+		)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, errA := lib.Prompt("unknown", "")
+		_, errB := lib.Prompt("translate", "3")
+
+		it.Then(t).Should(
+			it.True(errors.Is(errA, prompts.ErrNotFound)),
+			it.True(errors.Is(errB, prompts.ErrNotFound)),
+		)
+	})
+}
+
+func TestLibraryInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown field": "name: a\ntaks: typo",
+		"no name":       "task: Translate",
+		"bad template":  "name: a\ntask: \"{{.Lang\"",
+		"duplicate":     "name: a\nversion: 1\n---\nname: a\nversion: 1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := prompts.New().Parse([]byte(data))
+			it.Then(t).ShouldNot(it.Nil(err))
+		})
+	}
+
+	t.Run("Atomic", func(t *testing.T) {
+		lib := prompts.New()
+		errA := lib.Parse([]byte("name: a\n---\nname: b\ntask: \"{{.Lang\""))
+		errB := lib.Load(fstest.MapFS{
+			"a.yaml": {Data: []byte("name: a\nversion: 1")},
+			"b.yaml": {Data: []byte("name: a\nversion: 1")},
+		})
+
+		it.Then(t).Should(
+			it.Fail(func() error { return errA }),
+			it.Fail(func() error { return errB }),
+			it.Equal(len(lib.Names()), 0),
+		)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		_, err := prompts.Load(fstest.MapFS{"a.yaml": {Data: []byte("name: a\ntaks: typo")}})
+		it.Then(t).Should(it.Error(struct{}{}, err).Contain("a.yaml"))
+	})
+}
@@ -10,6 +10,7 @@ package autoconfig
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"testing/fstest"
 
@@ -82,11 +83,13 @@ func TestFromFile_YAML(t *testing.T) {
 		instances, err := FromFile(fsys, "config.yaml")
 		it.Then(t).Should(it.Nil(err)).ShouldNot(it.Nil(instances))
 
-		_, ok1 := instances.Model("model1")
-		_, ok2 := instances.Model("model2")
+		llm1, ok1 := instances.Model("model1")
+		llm2, ok2 := instances.Model("model2")
 		it.Then(t).Should(
 			it.Equal(ok1, true),
 			it.Equal(ok2, true),
+			it.Equal(chatter.ModelID(llm1), "mock-a"),
+			it.Equal(chatter.ModelID(llm2), "mock-b"),
 		)
 	})
 
@@ -184,6 +187,27 @@ func TestFromFile_NetRC(t *testing.T) {
 		)
 	})
 
+	t.Run("guardrail_netrc", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			".netrc": {Data: []byte(`machine mymodel
+  provider provider:mock
+  model test-model
+  guardrail gr-1
+  guardrail-version DRAFT
+  guardrail-trace true
+`)},
+		}
+		instances, err := FromFile(fsys, ".netrc")
+		it.Then(t).Must(it.Nil(err))
+
+		g := instances.Spec["mymodel"].Guardrail
+		it.Then(t).Should(
+			it.Equal(g.ID, "gr-1"),
+			it.Equal(g.Version, "DRAFT"),
+			it.Equal(g.Trace, true),
+		)
+	})
+
 	t.Run("unsupported_provider_netrc", func(t *testing.T) {
 		fsys := fstest.MapFS{
 			".netrc": {Data: []byte(`machine mymodel
@@ -280,6 +304,125 @@ func TestInstances_Usage(t *testing.T) {
 			it.Equal(total.InputTokens, 0),
 		)
 	})
+
+	t.Run("reset_reports_usage_per_interval", func(t *testing.T) {
+		llm, _ := instances.Model("model2")
+		_, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("hello")})
+		if err != nil {
+			t.Fatalf("prompt failed: %v", err)
+		}
+
+		total, breakdown, err := instances.ResetUsage()
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(len(breakdown), 2),
+			it.Equal(breakdown["model2"].InputTokens, 5),
+			it.Equal(total.InputTokens, breakdown["model1"].InputTokens+5),
+		)
+
+		total, breakdown = instances.Usage()
+		it.Then(t).Should(
+			it.Equal(total.InputTokens, 0),
+			it.Equal(len(breakdown), 0),
+		)
+	})
+}
+
+// middleware that wraps the instance
+type wrapper struct{ chatter.Chatter }
+
+func (w wrapper) Unwrap() chatter.Chatter { return w.Chatter }
+
+// instance that does not support usage reset
+type cumulative struct{ chatter.Chatter }
+
+func TestInstances_ResetUsage(t *testing.T) {
+	prompt := []chatter.Message{chatter.Text("hello")}
+
+	t.Run("unwraps_middlewares", func(t *testing.T) {
+		instances := MustMock(wrapper{wrapper{NewMock(nil)}})
+		llm, _ := instances.Model("base")
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		total, _, err := instances.ResetUsage()
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(total.InputTokens, 5),
+			it.Equal(llm.Usage().InputTokens, 0),
+		)
+	})
+
+	t.Run("fails_if_reset_is_not_supported", func(t *testing.T) {
+		instances := MustMock(cumulative{NewMock(nil)})
+		llm, _ := instances.Model("base")
+		_, err := llm.Prompt(context.Background(), prompt)
+		it.Then(t).Must(it.Nil(err))
+
+		_, _, err = instances.ResetUsage()
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("usage reset is not supported"),
+			it.Equal(llm.Usage().InputTokens, 5),
+		)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Instances.Cost
+
+func TestInstances_Cost(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.yaml": {Data: []byte(`
+custom:
+  provider: "provider:mock"
+  model: "mock-a"
+  price:
+    input: 2.0
+    reply: 10.0
+builtin:
+  provider: "provider:mock"
+  model: "gpt-4o-2024-08-06"
+unknown:
+  provider: "provider:mock"
+  model: "mock-b"
+`)},
+		".netrc": {Data: []byte(`machine custom
+  provider provider:mock
+  model mock-a
+  price-input 2.0
+  price-reply 10.0
+`)},
+	}
+
+	usage := map[string]chatter.Usage{
+		"custom":  {InputTokens: 1000000, ReplyTokens: 100000},
+		"builtin": {InputTokens: 1000000, ReplyTokens: 100000},
+		"unknown": {InputTokens: 1000000, ReplyTokens: 100000},
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		instances, err := FromFile(fsys, "config.yaml")
+		it.Then(t).Must(it.Nil(err))
+
+		total, cost := instances.Cost(usage)
+		it.Then(t).Should(
+			it.Equal(len(cost), 2),
+			it.Equal(cost["custom"], 3.0),
+			it.Equal(cost["builtin"], 3.5),
+			it.Equal(total, 6.5),
+		)
+	})
+
+	t.Run("netrc", func(t *testing.T) {
+		instances, err := FromFile(fsys, ".netrc")
+		it.Then(t).Must(it.Nil(err))
+
+		price, ok := instances.Price("custom")
+		it.Then(t).Should(
+			it.True(ok),
+			it.Equal(price, chatter.Price{Input: 2.0, Reply: 10.0}),
+		)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -325,4 +468,84 @@ func TestMustMock(t *testing.T) {
 			it.Equal(reply.String(), "fixed reply"),
 		)
 	})
+
+	t.Run("accepts_scriptable_mock", func(t *testing.T) {
+		script := NewScript().Return("scripted")
+		llm, _ := MustMock(script).Model("base")
+
+		reply, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("hello")})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "scripted"),
+			it.Equal(script.Pending(), 0),
+		)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Script
+
+func TestScript(t *testing.T) {
+	t.Run("replays_steps_in_order", func(t *testing.T) {
+		script := NewScript().
+			Invoke("weather", map[string]string{"city": "Helsinki"}).
+			Incomplete("It is").
+			Fail(errors.New("throttled")).
+			Return("It is sunny")
+
+		ctx := context.Background()
+		prompt := []chatter.Message{chatter.Text("weather?")}
+
+		reply, err := script.Prompt(ctx, prompt, chatter.Temperature(0.1))
+		it.Then(t).Must(it.Nil(err))
+		invoke := reply.Content[0].(chatter.Invoke)
+		it.Then(t).Should(
+			it.Equal(reply.Stage, chatter.LLM_INVOKE),
+			it.Equal(invoke.Cmd, "weather"),
+			it.Equal(string(invoke.Args.Value), `{"city":"Helsinki"}`),
+		)
+
+		reply, err = script.Prompt(ctx, prompt)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.Stage, chatter.LLM_INCOMPLETE),
+		)
+
+		_, err = script.Prompt(ctx, prompt)
+		it.Then(t).Should(it.Fail(func() error { return err }))
+
+		reply, err = script.Prompt(ctx, prompt)
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.Stage, chatter.LLM_RETURN),
+			it.Equal(reply.String(), "It is sunny"),
+			it.Equal(script.Pending(), 0),
+		)
+
+		_, err = script.Prompt(ctx, prompt)
+		it.Then(t).Should(it.Fail(func() error { return err }))
+
+		calls := script.Calls()
+		it.Then(t).Should(
+			it.Equal(len(calls), 5),
+			it.Equal(len(calls[0].Opts), 1),
+			it.Equal(calls[0].Prompt[0].String(), "weather?"),
+		)
+	})
+
+	t.Run("matches_predicates", func(t *testing.T) {
+		script := NewScript().
+			On(Contains("sunny")).Return("sunny").
+			On(Contains("rainy")).Return("rainy")
+
+		reply, err := script.Prompt(context.Background(), []chatter.Message{chatter.Text("is it rainy?")})
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "rainy"),
+			it.Equal(script.Pending(), 1),
+		)
+
+		_, err = script.Prompt(context.Background(), []chatter.Message{chatter.Text("is it cloudy?")})
+		it.Then(t).Should(it.Fail(func() error { return err }))
+	})
 }
@@ -10,6 +10,7 @@ package autoconfig
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -22,6 +23,8 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/jdxcode/netrc"
 	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/pricing"
+	"github.com/kshard/chatter/provider/bedrock/foundation/converse"
 )
 
 // LLM instances for the application
@@ -57,38 +60,116 @@ func (i *Instances) Build() error {
 	return nil
 }
 
+// Usage returns total and per instance usage accumulated by LLM instances.
 func (i Instances) Usage() (chatter.Usage, map[string]chatter.Usage) {
+	return i.usage(func(llm chatter.Chatter) chatter.Usage { return llm.Usage() })
+}
+
+// ResetUsage returns total and per instance usage accumulated since
+// the last reset and resets counters, allowing to report usage per interval.
+// Middlewares are unwrapped until the instance that supports reset
+// (see [chatter.ResetUsage]), the error is returned if any instance does not
+// support it. Usage of other instances is reset anyway.
+func (i Instances) ResetUsage() (chatter.Usage, map[string]chatter.Usage, error) {
+	var errs []error
+	total, usage := i.usage(func(llm chatter.Chatter) chatter.Usage {
+		u, err := chatter.ResetUsage(llm)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return u
+	})
+
+	return total, usage, errors.Join(errs...)
+}
+
+func (i Instances) usage(f func(chatter.Chatter) chatter.Usage) (chatter.Usage, map[string]chatter.Usage) {
 	total := chatter.Usage{}
 	usage := make(map[string]chatter.Usage)
 	for name, llm := range i.llms {
-		u := llm.Usage()
+		u := f(llm)
 		if u.InputTokens == 0 && u.ReplyTokens == 0 {
 			continue
 		}
 		usage[name] = u
-		total.InputTokens += u.InputTokens
-		total.ReplyTokens += u.ReplyTokens
+		total.Add(u)
 	}
 	return total, usage
 }
 
+// Price returns the price of the instance, either configured or built-in.
+func (i Instances) Price(name string) (chatter.Price, bool) {
+	spec, has := i.Spec[name]
+	if !has {
+		return chatter.Price{}, false
+	}
+
+	if spec.Price != nil {
+		return *spec.Price, true
+	}
+
+	return pricing.Lookup(spec.Model)
+}
+
+// Cost returns total and per instance cost in USD of the usage, as reported
+// by [Instances.Usage] or [Instances.ResetUsage]. Instances with unknown price
+// are not accounted.
+func (i Instances) Cost(usage map[string]chatter.Usage) (float64, map[string]float64) {
+	total := 0.0
+	cost := make(map[string]float64)
+	for name, u := range usage {
+		price, has := i.Price(name)
+		if !has {
+			continue
+		}
+		cost[name] = price.Cost(u)
+		total += cost[name]
+	}
+	return total, cost
+}
+
 func (i Instances) String() string {
 	var sb strings.Builder
 
 	total, usage := i.Usage()
+	totalCost, cost := i.Cost(usage)
 
 	fmt.Fprintf(&sb, "\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
-	fmt.Fprintf(&sb, "Usage: %s (input: %s | output: %s)\n", fmtInt(total.InputTokens+total.ReplyTokens), fmtInt(total.InputTokens), fmtInt(total.ReplyTokens))
+	fmt.Fprintf(&sb, "Usage: %s %s\n", fmtUsage(total), fmtCost(totalCost))
 	for name, llm := range usage {
-		input := llm.InputTokens
-		reply := llm.ReplyTokens
-		fmt.Fprintf(&sb, " - %s: %s (input: %s | output: %s)\n", name, fmtInt(input+reply), fmtInt(input), fmtInt(reply))
+		fmt.Fprintf(&sb, " - %s: %s", name, fmtUsage(llm))
+		if c, has := cost[name]; has {
+			fmt.Fprintf(&sb, " %s", fmtCost(c))
+		}
+		fmt.Fprintf(&sb, "\n")
 	}
 	fmt.Fprintf(&sb, "\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 
 	return sb.String()
 }
 
+func fmtUsage(u chatter.Usage) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s (input: %s | output: %s", fmtInt(u.InputTokens+u.ReplyTokens), fmtInt(u.InputTokens), fmtInt(u.ReplyTokens))
+	if u.CacheReadTokens > 0 || u.CacheWriteTokens > 0 {
+		fmt.Fprintf(&sb, " | cache read: %s | cache write: %s", fmtInt(u.CacheReadTokens), fmtInt(u.CacheWriteTokens))
+	}
+	if u.ReasoningTokens > 0 {
+		fmt.Fprintf(&sb, " | reasoning: %s", fmtInt(u.ReasoningTokens))
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+func fmtCost(c float64) string {
+	if c < 0.01 && c > 0 {
+		return fmt.Sprintf("$%.4f", c)
+	}
+	return fmt.Sprintf("$%.2f", c)
+}
+
 func fmtInt(n int) string {
 	if n >= 1000000 {
 		return fmt.Sprintf("%.1fM", float64(n)/1000000)
@@ -269,6 +350,24 @@ func FromNetRC(r io.Reader) (*Instances, error) {
 			}
 		}
 
+		var price *chatter.Price
+		if val := machine.Get("price-input"); len(val) != 0 {
+			price = &chatter.Price{}
+			price.Input, _ = strconv.ParseFloat(val, 64)
+			price.Reply, _ = strconv.ParseFloat(machine.Get("price-reply"), 64)
+			price.CacheRead, _ = strconv.ParseFloat(machine.Get("price-cache-read"), 64)
+			price.CacheWrite, _ = strconv.ParseFloat(machine.Get("price-cache-write"), 64)
+		}
+
+		var guardrail *converse.Guardrail
+		if val := machine.Get("guardrail"); len(val) != 0 {
+			guardrail = &converse.Guardrail{
+				ID:      val,
+				Version: machine.Get("guardrail-version"),
+				Trace:   machine.Get("guardrail-trace") == "true",
+			}
+		}
+
 		cfg.Spec[machine.Name] = Instance{
 			Name:       machine.Name,
 			Provider:   machine.Get("provider"),
@@ -278,6 +377,8 @@ func FromNetRC(r io.Reader) (*Instances, error) {
 			Secret:     machine.Get("secret"),
 			Timeout:    timeout,
 			Dimensions: dimensions,
+			Price:      price,
+			Guardrail:  guardrail,
 		}
 	}
 
@@ -298,6 +399,7 @@ func MustFromNetRC(r io.Reader) *Instances {
 }
 
 // Mock "base" llm for unit testing, it will echo the input by default, but can be configured to return a specific reply.
+// Any chatter.Chatter (e.g. scriptable mock [Script]) is used as "base" llm as-is.
 func MustMock(v any) *Instances {
 	cfg := Instances{
 		Spec: make(map[string]Instance),
@@ -307,7 +409,12 @@ func MustMock(v any) *Instances {
 		Name:     "base",
 		Provider: "provider:mock",
 	}
-	cfg.llms["base"] = NewMock(v)
+	switch llm := v.(type) {
+	case chatter.Chatter:
+		cfg.llms["base"] = llm
+	default:
+		cfg.llms["base"] = NewMock(v)
+	}
 
 	return &cfg
 }
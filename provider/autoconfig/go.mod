@@ -3,15 +3,14 @@ module github.com/kshard/chatter/provider/autoconfig
 go 1.25.0
 
 require (
-	github.com/fogfish/curie/v2 v2.1.3
 	github.com/fogfish/gurl/v2 v2.10.0
 	github.com/fogfish/it/v2 v2.2.4
 	github.com/goccy/go-yaml v1.19.2
 	github.com/jdxcode/netrc v1.0.0
-	github.com/kshard/chatter v0.11.2
-	github.com/kshard/chatter/provider/bedrock v0.10.1
-	github.com/kshard/chatter/provider/google v0.1.2
-	github.com/kshard/chatter/provider/openai v0.10.1
+	github.com/kshard/chatter v0.12.0
+	github.com/kshard/chatter/provider/bedrock v0.11.0
+	github.com/kshard/chatter/provider/google v0.2.0
+	github.com/kshard/chatter/provider/openai v0.11.0
 )
 
 require (
@@ -72,3 +71,10 @@ require (
 	google.golang.org/grpc v1.79.3 // indirect
 	google.golang.org/protobuf v1.36.11 // indirect
 )
+
+replace (
+	github.com/kshard/chatter => ../..
+	github.com/kshard/chatter/provider/bedrock => ../bedrock
+	github.com/kshard/chatter/provider/google => ../google
+	github.com/kshard/chatter/provider/openai => ../openai
+)
@@ -60,6 +60,13 @@ type Instance struct {
 
 	// Dimensions for embedding models. For example, `1024` for Titan embedding.
 	Dimensions int `json:"dimensions,omitempty" yaml:"dimensions,omitempty"`
+
+	// Price of the model in USD per million tokens, overrides the built-in
+	// price table (see github.com/kshard/chatter/pricing).
+	Price *chatter.Price `json:"price,omitempty" yaml:"price,omitempty"`
+
+	// Bedrock Converse specific, guardrail applied to every prompt.
+	Guardrail *converse.Guardrail `json:"guardrail,omitempty" yaml:"guardrail,omitempty"`
 }
 
 // Automatically create a Chatter instance based on the configuration.
@@ -70,13 +77,17 @@ func NewInstance(c Instance) (chatter.Chatter, error) {
 
 	switch c.Provider {
 	case "provider:mock":
-		return &Mock{}, nil
+		return &Mock{model: c.Model}, nil
 
 	case "provider:bedrock/embedding/titan":
 		return titan.New(c.Model, c.Dimensions, bedrock.WithRegion(c.Region))
 
 	case "provider:bedrock/foundation/converse":
-		return converse.New(c.Model, converse.WithRegion(c.Region))
+		opts := []converse.Option{converse.WithRegion(c.Region)}
+		if c.Guardrail != nil {
+			opts = append(opts, converse.WithGuardrail(*c.Guardrail))
+		}
+		return converse.New(c.Model, opts...)
 
 	case "provider:bedrock/foundation/llama":
 		return llama.New(c.Model, bedrock.WithRegion(c.Region))
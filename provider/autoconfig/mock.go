@@ -19,7 +19,8 @@ import (
 
 // Mock is a simple mock LLM that echoes the input.
 type Mock struct {
-	usage chatter.Usage
+	usage chatter.Meter
+	model string
 	reply any
 }
 
@@ -27,8 +28,15 @@ func NewMock(reply any) *Mock {
 	return &Mock{reply: reply}
 }
 
+// Model ID as defined by the configuration
+func (m *Mock) ModelID() string { return m.model }
+
 func (m *Mock) Usage() chatter.Usage {
-	return m.usage
+	return m.usage.Usage()
+}
+
+func (m *Mock) ResetUsage() chatter.Usage {
+	return m.usage.Reset()
 }
 
 func (m *Mock) Prompt(ctx context.Context, prompt []chatter.Message, opt ...chatter.Opt) (*chatter.Reply, error) {
@@ -53,15 +61,15 @@ func (m *Mock) Prompt(ctx context.Context, prompt []chatter.Message, opt ...chat
 		reply = string(b)
 	}
 
-	m.usage.InputTokens += len(reply)
-	m.usage.ReplyTokens += len(reply)
+	usage := chatter.Usage{
+		InputTokens: len(reply),
+		ReplyTokens: len(reply),
+	}
+	m.usage.Add(usage)
 
 	return &chatter.Reply{
 		Stage: chatter.LLM_RETURN,
-		Usage: chatter.Usage{
-			InputTokens: len(reply),
-			ReplyTokens: len(reply),
-		},
+		Usage: usage,
 		Content: []chatter.Content{
 			chatter.Text(reply),
 		},
@@ -0,0 +1,191 @@
+//
+// Copyright (C) 2024 - 2026 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package autoconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kshard/chatter"
+)
+
+// Predicate on the prompt received by the scriptable mock.
+type Predicate func(prompt []chatter.Message) bool
+
+// Contains matches prompts, which latest message contains the text.
+func Contains(text string) Predicate {
+	return func(prompt []chatter.Message) bool {
+		if len(prompt) == 0 {
+			return false
+		}
+		return strings.Contains(prompt[len(prompt)-1].String(), text)
+	}
+}
+
+// Call received by the scriptable mock.
+type Call struct {
+	Prompt []chatter.Message
+	Opts   []chatter.Opt
+}
+
+// Script is a scriptable mock LLM for unit testing of agent loops. It replies
+// with the queue of scripted steps: text, tool invocations, incomplete
+// replies or errors. Each step is used once, the step is matched by optional
+// predicate, otherwise steps are replayed in order. Received calls are
+// recorded for assertions.
+//
+//	llm := autoconfig.NewScript().
+//		Invoke("weather", map[string]string{"city": "Helsinki"}).
+//		On(autoconfig.Contains("sunny")).Return("It is sunny in Helsinki")
+//
+//	cfg := autoconfig.MustMock(llm)
+type Script struct {
+	mu    sync.Mutex
+	on    Predicate
+	steps []step
+	calls []Call
+	usage chatter.Meter
+}
+
+type step struct {
+	on    Predicate
+	reply *chatter.Reply
+	err   error
+}
+
+func NewScript() *Script {
+	return &Script{}
+}
+
+// On sets the predicate of the next scripted step.
+func (s *Script) On(predicate Predicate) *Script {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.on = predicate
+	return s
+}
+
+// Reply appends arbitrary reply to the script.
+func (s *Script) Reply(reply *chatter.Reply) *Script {
+	return s.push(step{reply: reply})
+}
+
+// Return appends final text reply to the script.
+func (s *Script) Return(text string) *Script {
+	return s.push(step{reply: &chatter.Reply{
+		Stage:   chatter.LLM_RETURN,
+		Usage:   chatter.Usage{ReplyTokens: len(text)},
+		Content: []chatter.Content{chatter.Text(text)},
+	}})
+}
+
+// Incomplete appends truncated text reply to the script.
+func (s *Script) Incomplete(text string) *Script {
+	return s.push(step{reply: &chatter.Reply{
+		Stage:   chatter.LLM_INCOMPLETE,
+		Usage:   chatter.Usage{ReplyTokens: len(text)},
+		Content: []chatter.Content{chatter.Text(text)},
+	}})
+}
+
+// Invoke appends tool invocation to the script, arguments are encoded as JSON.
+func (s *Script) Invoke(cmd string, args any) *Script {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return s.push(step{err: err})
+	}
+
+	s.mu.Lock()
+	id := fmt.Sprintf("call-%d", len(s.steps)+1)
+	s.mu.Unlock()
+
+	return s.push(step{reply: &chatter.Reply{
+		Stage: chatter.LLM_INVOKE,
+		Usage: chatter.Usage{ReplyTokens: len(b)},
+		Content: []chatter.Content{
+			chatter.Invoke{Cmd: cmd, Args: chatter.Json{ID: id, Source: cmd, Value: b}},
+		},
+	}})
+}
+
+// Fail appends error to the script.
+func (s *Script) Fail(err error) *Script {
+	return s.push(step{err: err})
+}
+
+func (s *Script) push(st step) *Script {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st.on = s.on
+	s.on = nil
+	s.steps = append(s.steps, st)
+	return s
+}
+
+// Calls returns calls received by the mock.
+func (s *Script) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call{}, s.calls...)
+}
+
+// Pending returns number of scripted steps, which are not used yet.
+func (s *Script) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.steps)
+}
+
+func (s *Script) Usage() chatter.Usage {
+	return s.usage.Usage()
+}
+
+func (s *Script) ResetUsage() chatter.Usage {
+	return s.usage.Reset()
+}
+
+func (s *Script) Prompt(ctx context.Context, prompt []chatter.Message, opts ...chatter.Opt) (*chatter.Reply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, Call{
+		Prompt: append([]chatter.Message{}, prompt...),
+		Opts:   append([]chatter.Opt{}, opts...),
+	})
+
+	for i, st := range s.steps {
+		if st.on != nil && !st.on(prompt) {
+			continue
+		}
+
+		s.steps = append(s.steps[:i], s.steps[i+1:]...)
+		if st.err != nil {
+			return nil, st.err
+		}
+
+		usage := st.reply.Usage
+		for _, msg := range prompt {
+			usage.InputTokens += len(msg.String())
+		}
+		s.usage.Add(usage)
+
+		reply := *st.reply
+		reply.Usage = usage
+		return &reply, nil
+	}
+
+	return nil, fmt.Errorf("unexpected prompt, no scripted reply (%d pending)", len(s.steps))
+}
@@ -141,7 +141,7 @@ func (p *Provider[A, B]) Prepare() (*Job[A, B], error) {
 		JobName: aws.String(u),
 	}
 
-	srv := provider.New(p.factory, decoder[B]{}, &job[A, B]{w: json.NewEncoder(fd)})
+	srv := provider.New(p.factory, decoder[B]{}, &job[A, B]{w: json.NewEncoder(fd)}).WithModel(p.client.model)
 	job := &Job[A, B]{
 		Provider: srv,
 		fd:       fd,
@@ -52,6 +52,11 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+func (codec *encoder) AsCachePoint(chatter.CachePoint) error {
+	// Embeddings don't support prompt caching
+	return nil
+}
+
 func (codec *encoder) Build() *input {
 	codec.req.Text = codec.w.String()
 	return &codec.req
@@ -46,5 +46,5 @@ func New(model string, dimensions int, opts ...bedrock.Option) (*Titan, error) {
 		return nil, err
 	}
 
-	return provider.New(factory(dimensions), decoder{}, service), nil
+	return provider.New(factory(dimensions), decoder{}, service).WithModel(model), nil
 }
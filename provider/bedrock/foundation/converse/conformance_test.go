@@ -0,0 +1,173 @@
+//
+// Copyright 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package converse
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio/provider"
+	"github.com/kshard/chatter/aio/provider/providertest"
+)
+
+func TestConformance(t *testing.T) {
+	report := providertest.Run(t, providertest.Kit[*bedrockruntime.ConverseInput, *bedrockruntime.ConverseOutput]{
+		Factory:  factory("anthropic.claude-3-haiku", nil),
+		Decoder:  decoder{},
+		Inspect:  inspect,
+		Response: response,
+	})
+
+	report.Expect(t,
+		providertest.Stratum,
+		providertest.MultiTurn,
+		providertest.ReplyReplay,
+		providertest.Tools,
+		providertest.ToolInvokeReplay,
+		providertest.ToolAnswer,
+		providertest.ToolInvoke,
+		providertest.Temperature,
+		providertest.TopP,
+		providertest.MaxTokens,
+		providertest.StopSequences,
+		providertest.StopReturn,
+		providertest.StopIncomplete,
+		providertest.StopInvoke,
+		providertest.Usage,
+		providertest.Reasoning,
+		providertest.ReasoningReplay,
+		providertest.ReasoningOption,
+		providertest.CachePoint,
+	)
+}
+
+func inspect(req *bedrockruntime.ConverseInput) providertest.Request {
+	var r providertest.Request
+
+	for _, block := range req.System {
+		switch v := block.(type) {
+		case *types.SystemContentBlockMemberText:
+			r.System += v.Value
+		case *types.SystemContentBlockMemberCachePoint:
+			r.CachePoints++
+		}
+	}
+
+	for _, msg := range req.Messages {
+		m := providertest.Message{Role: string(msg.Role)}
+		for _, block := range msg.Content {
+			switch v := block.(type) {
+			case *types.ContentBlockMemberText:
+				m.Text += v.Value
+			case *types.ContentBlockMemberToolUse:
+				m.Invoke = append(m.Invoke, aws.ToString(v.Value.Name))
+			case *types.ContentBlockMemberToolResult:
+				m.Role = "tool"
+				m.Yield = append(m.Yield, aws.ToString(v.Value.ToolUseId))
+			case *types.ContentBlockMemberReasoningContent:
+				if text, ok := v.Value.(*types.ReasoningContentBlockMemberReasoningText); ok {
+					m.Reasoning = append(m.Reasoning, chatter.Reasoning{
+						Text:      aws.ToString(text.Value.Text),
+						Signature: aws.ToString(text.Value.Signature),
+					})
+				}
+			case *types.ContentBlockMemberCachePoint:
+				r.CachePoints++
+			}
+		}
+		r.Messages = append(r.Messages, m)
+	}
+
+	if req.ToolConfig != nil {
+		for _, tool := range req.ToolConfig.Tools {
+			switch v := tool.(type) {
+			case *types.ToolMemberToolSpec:
+				r.Tools = append(r.Tools, aws.ToString(v.Value.Name))
+			case *types.ToolMemberCachePoint:
+				r.CachePoints++
+			}
+		}
+	}
+
+	if cfg := req.InferenceConfig; cfg != nil {
+		r.Inferrer = provider.Inferrer{
+			Temperature:   float64(aws.ToFloat32(cfg.Temperature)),
+			TopP:          float64(aws.ToFloat32(cfg.TopP)),
+			MaxTokens:     int(aws.ToInt32(cfg.MaxTokens)),
+			StopSequences: cfg.StopSequences,
+		}
+	}
+
+	if req.AdditionalModelRequestFields != nil {
+		var fields struct {
+			Thinking struct {
+				BudgetTokens int `json:"budget_tokens"`
+			} `json:"thinking"`
+		}
+		if b, err := req.AdditionalModelRequestFields.MarshalSmithyDocument(); err == nil {
+			if err := json.Unmarshal(b, &fields); err == nil {
+				r.Inferrer.ReasoningBudget = fields.Thinking.BudgetTokens
+			}
+		}
+	}
+
+	return r
+}
+
+func response(r providertest.Response) (*bedrockruntime.ConverseOutput, error) {
+	var reason types.StopReason
+	switch r.Stage {
+	case chatter.LLM_RETURN:
+		reason = types.StopReasonEndTurn
+	case chatter.LLM_INCOMPLETE:
+		reason = types.StopReasonMaxTokens
+	case chatter.LLM_INVOKE:
+		reason = types.StopReasonToolUse
+	default:
+		return nil, fmt.Errorf("unsupported stage %s", r.Stage)
+	}
+
+	content := []types.ContentBlock{}
+	for _, reasoning := range r.Reasoning {
+		content = append(content, encodeReasoning(reasoning))
+	}
+	if r.Text != "" {
+		content = append(content, &types.ContentBlockMemberText{Value: r.Text})
+	}
+	for _, invoke := range r.Invoke {
+		var args map[string]any
+		if err := json.Unmarshal(invoke.Args.Value, &args); err != nil {
+			return nil, err
+		}
+		content = append(content, &types.ContentBlockMemberToolUse{
+			Value: types.ToolUseBlock{
+				ToolUseId: aws.String(invoke.Args.ID),
+				Name:      aws.String(invoke.Cmd),
+				Input:     document.NewLazyDocument(args),
+			},
+		})
+	}
+
+	return &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{
+			Value: types.Message{Role: types.ConversationRoleAssistant, Content: content},
+		},
+		StopReason: reason,
+		Usage: &types.TokenUsage{
+			InputTokens:  aws.Int32(int32(r.Usage.InputTokens)),
+			OutputTokens: aws.Int32(int32(r.Usage.ReplyTokens)),
+		},
+	}, nil
+}
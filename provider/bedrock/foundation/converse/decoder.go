@@ -9,6 +9,7 @@
 package converse
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 
@@ -28,9 +29,22 @@ func (decoder decoder) Decode(result *bedrockruntime.ConverseOutput) (*chatter.R
 	}
 	reply.Content = content
 
+	if result.Trace != nil && result.Trace.Guardrail != nil {
+		trace, err := json.Marshal(result.Trace.Guardrail)
+		if err != nil {
+			return nil, err
+		}
+		reply.Content = append(reply.Content, chatter.Json{Source: "guardrail", Value: trace})
+	}
+
 	if result.Usage != nil {
-		reply.Usage.InputTokens += int(aws.ToInt32(result.Usage.InputTokens))
+		// Bedrock reports input tokens excluding cached ones
+		cacheRead := int(aws.ToInt32(result.Usage.CacheReadInputTokens))
+		cacheWrite := int(aws.ToInt32(result.Usage.CacheWriteInputTokens))
+		reply.Usage.InputTokens += int(aws.ToInt32(result.Usage.InputTokens)) + cacheRead + cacheWrite
 		reply.Usage.ReplyTokens += int(aws.ToInt32(result.Usage.OutputTokens))
+		reply.Usage.CacheReadTokens += cacheRead
+		reply.Usage.CacheWriteTokens += cacheWrite
 	}
 
 	return reply, nil
@@ -46,6 +60,8 @@ func decodeStage(reason types.StopReason) chatter.Stage {
 		return chatter.LLM_INCOMPLETE
 	case types.StopReasonToolUse:
 		return chatter.LLM_INVOKE
+	case types.StopReasonGuardrailIntervened, types.StopReasonContentFiltered:
+		return chatter.LLM_GUARDRAIL
 	default:
 		return chatter.LLM_ERROR
 	}
@@ -88,6 +104,10 @@ func decodeContent(block types.ContentBlock) (chatter.Content, error) {
 			},
 			Message: v,
 		}, nil
+
+	case *types.ContentBlockMemberReasoningContent:
+		return decodeReasoning(v.Value), nil
+
 	default:
 		slog.Warn("chatter does not support aws bedrock content type",
 			slog.String("type", fmt.Sprintf("%T", block)),
@@ -95,3 +115,20 @@ func decodeContent(block types.ContentBlock) (chatter.Content, error) {
 		return nil, nil
 	}
 }
+
+func decodeReasoning(block types.ReasoningContentBlock) chatter.Content {
+	switch v := block.(type) {
+	case *types.ReasoningContentBlockMemberReasoningText:
+		return chatter.Reasoning{
+			Text:      aws.ToString(v.Value.Text),
+			Signature: aws.ToString(v.Value.Signature),
+		}
+	case *types.ReasoningContentBlockMemberRedactedContent:
+		return chatter.Reasoning{Redacted: v.Value}
+	default:
+		slog.Warn("chatter does not support aws bedrock reasoning type",
+			slog.String("type", fmt.Sprintf("%T", block)),
+		)
+		return nil
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
 )
 
 func TestDecoderBasicTextResponse(t *testing.T) {
@@ -56,6 +57,80 @@ func TestDecoderBasicTextResponse(t *testing.T) {
 	)
 }
 
+func TestDecoderCacheUsage(t *testing.T) {
+	input := &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{
+			Value: types.Message{
+				Role:    types.ConversationRoleAssistant,
+				Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: "ok"}},
+			},
+		},
+		StopReason: types.StopReasonEndTurn,
+		Usage: &types.TokenUsage{
+			InputTokens:           aws.Int32(15),
+			OutputTokens:          aws.Int32(8),
+			CacheReadInputTokens:  aws.Int32(1000),
+			CacheWriteInputTokens: aws.Int32(200),
+		},
+	}
+
+	reply, err := decoder{}.Decode(input)
+
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Json(reply.Usage).Equiv(`{
+			"inputTokens": 1215,
+			"replyTokens": 8,
+			"cacheReadTokens": 1000,
+			"cacheWriteTokens": 200
+		}`),
+	)
+}
+
+func TestDecoderReasoning(t *testing.T) {
+	input := &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{
+			Value: types.Message{
+				Role: types.ConversationRoleAssistant,
+				Content: []types.ContentBlock{
+					&types.ContentBlockMemberReasoningContent{
+						Value: &types.ReasoningContentBlockMemberReasoningText{
+							Value: types.ReasoningTextBlock{
+								Text:      aws.String("The user asks for a sum."),
+								Signature: aws.String("c2lnbmF0dXJl"),
+							},
+						},
+					},
+					&types.ContentBlockMemberReasoningContent{
+						Value: &types.ReasoningContentBlockMemberRedactedContent{
+							Value: []byte{1, 2, 3},
+						},
+					},
+					&types.ContentBlockMemberText{Value: "4"},
+				},
+			},
+		},
+		StopReason: types.StopReasonEndTurn,
+	}
+
+	reply, err := decoder{}.Decode(input)
+
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(reply.String(), "4"),
+		it.Equal(reply.Reasoning(), "The user asks for a sum."),
+		it.Json(reply).Equiv(`{
+			"stage": "return",
+			"usage": {},
+			"content": [
+				{"text": "The user asks for a sum.", "signature": "c2lnbmF0dXJl"},
+				{"redacted": "AQID"},
+				{"text": "4"}
+			]
+		}`),
+	)
+}
+
 func TestDecoderToolInvocationResponse(t *testing.T) {
 	toolInput := map[string]interface{}{
 		"location": "San Francisco",
@@ -110,6 +185,54 @@ func TestDecoderToolInvocationResponse(t *testing.T) {
 	)
 }
 
+func TestDecoderGuardrailTrace(t *testing.T) {
+	input := &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{
+			Value: types.Message{
+				Role: types.ConversationRoleAssistant,
+				Content: []types.ContentBlock{
+					&types.ContentBlockMemberText{Value: "Sorry, the model cannot answer this question."},
+				},
+			},
+		},
+		StopReason: types.StopReasonGuardrailIntervened,
+		Trace: &types.ConverseTrace{
+			Guardrail: &types.GuardrailTraceAssessment{
+				InputAssessment: map[string]types.GuardrailAssessment{
+					"gr-1": {
+						TopicPolicy: &types.GuardrailTopicPolicyAssessment{
+							Topics: []types.GuardrailTopic{
+								{Name: aws.String("finance"), Action: types.GuardrailTopicPolicyActionBlocked},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	reply, err := decoder{}.Decode(input)
+	it.Then(t).Must(
+		it.Nil(err),
+		it.Equal(reply.Stage, chatter.LLM_GUARDRAIL),
+		it.Equal(len(reply.Content), 2),
+	)
+
+	trace := reply.Content[1].(chatter.Json)
+	it.Then(t).Should(
+		it.Equal(trace.Source, "guardrail"),
+		it.Json(trace.Value).Equiv(`{
+			"InputAssessment": {
+				"gr-1": {
+					"TopicPolicy": {
+						"Topics": [{"Name": "finance", "Action": "BLOCKED"}]
+					}
+				}
+			}
+		}`),
+	)
+}
+
 func TestDecoderStageMapping(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -136,6 +259,11 @@ func TestDecoderStageMapping(t *testing.T) {
 			stopReason:    types.StopReasonToolUse,
 			expectedStage: "invoke",
 		},
+		{
+			name:          "guardrail_intervened_returns_guardrail",
+			stopReason:    types.StopReasonGuardrailIntervened,
+			expectedStage: "guardrail",
+		},
 		{
 			name:          "unknown_reason_returns_error",
 			stopReason:    "unknown_reason",
@@ -11,6 +11,8 @@ package converse
 import (
 	"encoding/json"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
@@ -59,6 +61,32 @@ func (codec *encoder) WithInferrer(inf provider.Inferrer) {
 	if inf.StopSequences != nil {
 		codec.req.InferenceConfig.StopSequences = inf.StopSequences
 	}
+
+	if effort, budget := inf.Reasoning(); budget > 0 {
+		codec.req.AdditionalModelRequestFields = encodeReasoningConfig(
+			aws.ToString(codec.req.ModelId), effort, budget,
+		)
+	}
+}
+
+// reasoning is configured through model specific request fields,
+// Amazon Nova uses effort, other models (Anthropic) use token budget.
+func encodeReasoningConfig(model, effort string, budget int) document.Interface {
+	if strings.Contains(model, "amazon.nova") {
+		return document.NewLazyDocument(map[string]any{
+			"reasoningConfig": map[string]any{
+				"type":               "enabled",
+				"maxReasoningEffort": effort,
+			},
+		})
+	}
+
+	return document.NewLazyDocument(map[string]any{
+		"thinking": map[string]any{
+			"type":          "enabled",
+			"budget_tokens": budget,
+		},
+	})
 }
 
 func (codec *encoder) WithCommand(cmd chatter.Cmd) {
@@ -159,6 +187,14 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 			msg.Content = append(msg.Content,
 				&types.ContentBlockMemberText{Value: string(v)},
 			)
+		case chatter.Invoke:
+			cb, err := encodeInvoke(v)
+			if err != nil {
+				return err
+			}
+			msg.Content = append(msg.Content, cb)
+		case chatter.Reasoning:
+			msg.Content = append(msg.Content, encodeReasoning(v))
 		case interface{ RawMessage() any }:
 			if cb, ok := v.RawMessage().(types.ContentBlock); ok {
 				msg.Content = append(msg.Content, cb)
@@ -170,6 +206,71 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+// encodes invocation, the original message is used if available, otherwise
+// tool use is replayed from provider-neutral form (e.g. restored history).
+func encodeInvoke(invoke chatter.Invoke) (types.ContentBlock, error) {
+	if cb, ok := invoke.RawMessage().(types.ContentBlock); ok {
+		return cb, nil
+	}
+
+	var input any
+	if len(invoke.Args.Value) > 0 {
+		if err := json.Unmarshal(invoke.Args.Value, &input); err != nil {
+			return nil, err
+		}
+	}
+
+	return &types.ContentBlockMemberToolUse{
+		Value: types.ToolUseBlock{
+			ToolUseId: aws.String(invoke.Args.ID),
+			Name:      aws.String(invoke.Cmd),
+			Input:     document.NewLazyDocument(input),
+		},
+	}, nil
+}
+
+// encodes reasoning, the block is replayed unchanged with its signature
+func encodeReasoning(reasoning chatter.Reasoning) types.ContentBlock {
+	if len(reasoning.Redacted) > 0 {
+		return &types.ContentBlockMemberReasoningContent{
+			Value: &types.ReasoningContentBlockMemberRedactedContent{Value: reasoning.Redacted},
+		}
+	}
+
+	block := types.ReasoningTextBlock{Text: aws.String(reasoning.Text)}
+	if reasoning.Signature != "" {
+		block.Signature = aws.String(reasoning.Signature)
+	}
+
+	return &types.ContentBlockMemberReasoningContent{
+		Value: &types.ReasoningContentBlockMemberReasoningText{Value: block},
+	}
+}
+
+// AsCachePoint places the cache checkpoint after preceding block: the latest
+// message, the system prompt or the tools, if there are no messages yet.
+func (codec *encoder) AsCachePoint(point chatter.CachePoint) error {
+	block := types.CachePointBlock{Type: types.CachePointTypeDefault}
+	switch {
+	case point.TTL > 5*time.Minute:
+		block.Ttl = types.CacheTTLOneHour
+	case point.TTL > 0:
+		block.Ttl = types.CacheTTLFiveMinutes
+	}
+
+	switch {
+	case len(codec.req.Messages) > 0:
+		msg := &codec.req.Messages[len(codec.req.Messages)-1]
+		msg.Content = append(msg.Content, &types.ContentBlockMemberCachePoint{Value: block})
+	case len(codec.req.System) > 0:
+		codec.req.System = append(codec.req.System, &types.SystemContentBlockMemberCachePoint{Value: block})
+	case codec.req.ToolConfig != nil && len(codec.req.ToolConfig.Tools) > 0:
+		codec.req.ToolConfig.Tools = append(codec.req.ToolConfig.Tools, &types.ToolMemberCachePoint{Value: block})
+	}
+
+	return nil
+}
+
 func (codec *encoder) Build() *bedrockruntime.ConverseInput {
 	return codec.req
 }
@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 	"github.com/fogfish/it/v2"
@@ -176,6 +177,138 @@ func TestEncoderReplyMessage(t *testing.T) {
 	it.Then(t).Should(it.Equal(contentBlock.Value, "The code is vulnerable to SQL injection. Use parameterized queries."))
 }
 
+func TestEncoderReplyInvoke(t *testing.T) {
+	f, err := factory("test-model", nil)()
+	it.Then(t).Must(it.Nil(err))
+
+	// Invoke restored from storage has no provider specific message
+	reply := &chatter.Reply{
+		Stage: chatter.LLM_INVOKE,
+		Content: []chatter.Content{
+			chatter.Invoke{
+				Cmd: "weather_check",
+				Args: chatter.Json{
+					ID:    "tool-call-1",
+					Value: json.RawMessage(`{"location":"Helsinki"}`),
+				},
+			},
+		},
+	}
+
+	err = f.AsReply(reply)
+	it.Then(t).Must(it.Nil(err))
+
+	req := f.Build()
+	it.Then(t).Should(
+		it.Equal(len(req.Messages), 1),
+		it.Equal(len(req.Messages[0].Content), 1),
+	)
+
+	tool := req.Messages[0].Content[0].(*types.ContentBlockMemberToolUse)
+	args, err := tool.Value.Input.MarshalSmithyDocument()
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(*tool.Value.ToolUseId, "tool-call-1"),
+		it.Equal(*tool.Value.Name, "weather_check"),
+		it.Equal(string(args), `{"location":"Helsinki"}`),
+	)
+}
+
+func TestEncoderReasoningConfig(t *testing.T) {
+	for model, expected := range map[string]string{
+		"anthropic.claude-sonnet-4":   `{"thinking":{"budget_tokens":4096,"type":"enabled"}}`,
+		"us.amazon.nova-premier-v1:0": `{"reasoningConfig":{"maxReasoningEffort":"medium","type":"enabled"}}`,
+	} {
+		f, err := factory(model, nil)()
+		it.Then(t).Must(it.Nil(err))
+
+		f.WithInferrer(provider.Inferrer{ReasoningEffort: "medium"})
+
+		req := f.Build()
+		fields, err := req.AdditionalModelRequestFields.MarshalSmithyDocument()
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(fields), expected),
+		)
+	}
+
+	f, err := factory("test-model", nil)()
+	it.Then(t).Must(it.Nil(err))
+
+	f.WithInferrer(provider.Inferrer{Temperature: 0.5})
+	it.Then(t).Should(
+		it.Nil(f.Build().AdditionalModelRequestFields),
+	)
+}
+
+func TestEncoderReplyReasoning(t *testing.T) {
+	f, err := factory("test-model", nil)()
+	it.Then(t).Must(it.Nil(err))
+
+	reply := &chatter.Reply{
+		Stage: chatter.LLM_RETURN,
+		Content: []chatter.Content{
+			chatter.Reasoning{Text: "The user asks for a sum.", Signature: "c2lnbmF0dXJl"},
+			chatter.Reasoning{Redacted: []byte{1, 2, 3}},
+			chatter.Text("4"),
+		},
+	}
+
+	err = f.AsReply(reply)
+	it.Then(t).Must(it.Nil(err))
+
+	req := f.Build()
+	it.Then(t).Should(
+		it.Equal(len(req.Messages), 1),
+		it.Equal(len(req.Messages[0].Content), 3),
+	)
+
+	text := req.Messages[0].Content[0].(*types.ContentBlockMemberReasoningContent).
+		Value.(*types.ReasoningContentBlockMemberReasoningText)
+	redacted := req.Messages[0].Content[1].(*types.ContentBlockMemberReasoningContent).
+		Value.(*types.ReasoningContentBlockMemberRedactedContent)
+	it.Then(t).Should(
+		it.Equal(*text.Value.Text, "The user asks for a sum."),
+		it.Equal(*text.Value.Signature, "c2lnbmF0dXJl"),
+		it.Seq(redacted.Value).Equal(1, 2, 3),
+	)
+}
+
+func TestEncoderCachePoint(t *testing.T) {
+	registry := chatter.Registry{
+		{Cmd: "search", About: "Search the web", Schema: json.RawMessage(`{"type": "object"}`)},
+	}
+
+	f, err := factory("test-model", registry)()
+	it.Then(t).Must(it.Nil(err))
+
+	it.Then(t).Must(
+		it.Nil(f.AsCachePoint(chatter.CachePoint{})),
+		it.Nil(f.AsStratum(chatter.Stratum("You are a helpful assistant"))),
+		it.Nil(f.AsCachePoint(chatter.CachePoint{TTL: time.Hour})),
+		it.Nil(f.AsText(chatter.Text("Long document"))),
+		it.Nil(f.AsCachePoint(chatter.CachePoint{TTL: time.Minute})),
+	)
+
+	req := f.Build()
+	it.Then(t).Should(
+		it.Equal(len(req.ToolConfig.Tools), 2),
+		it.Equal(len(req.System), 2),
+		it.Equal(len(req.Messages), 1),
+		it.Equal(len(req.Messages[0].Content), 2),
+	)
+
+	tools := req.ToolConfig.Tools[1].(*types.ToolMemberCachePoint)
+	system := req.System[1].(*types.SystemContentBlockMemberCachePoint)
+	message := req.Messages[0].Content[1].(*types.ContentBlockMemberCachePoint)
+	it.Then(t).Should(
+		it.Equal(tools.Value.Type, types.CachePointTypeDefault),
+		it.Equal(tools.Value.Ttl, ""),
+		it.Equal(system.Value.Ttl, types.CacheTTLOneHour),
+		it.Equal(message.Value.Ttl, types.CacheTTLFiveMinutes),
+	)
+}
+
 func TestEncoderToolConfiguration(t *testing.T) {
 	registry := chatter.Registry{
 		{
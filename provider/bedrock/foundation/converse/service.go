@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 	"github.com/fogfish/opts"
 	"github.com/kshard/chatter"
 	"github.com/kshard/chatter/aio/provider"
@@ -39,8 +40,25 @@ var (
 
 	// Set command-line registry
 	WithRegistry = opts.ForType[Service, chatter.Registry]()
+
+	// Set guardrail applied to every prompt
+	WithGuardrail = opts.ForType[Service, Guardrail]()
 )
 
+// Guardrail configuration of AWS Bedrock. The guardrail intervention is
+// reported as [chatter.LLM_GUARDRAIL] stage, the trace assessment (if enabled)
+// is appended to the reply as [chatter.Json] content with "guardrail" source.
+type Guardrail struct {
+	// Identifier or ARN of the guardrail
+	ID string `json:"id" yaml:"id"`
+
+	// Version of the guardrail (e.g. DRAFT or 1)
+	Version string `json:"version" yaml:"version"`
+
+	// Enables the trace assessment of the guardrail
+	Trace bool `json:"trace,omitempty" yaml:"trace,omitempty"`
+}
+
 func optsFromRegion(c *Service, region string) error {
 	cfg, err := config.LoadDefaultConfig(
 		context.Background(),
@@ -66,12 +84,30 @@ type Runtime interface {
 }
 
 type Service struct {
-	api      Runtime
-	registry chatter.Registry
+	api       Runtime
+	registry  chatter.Registry
+	guardrail Guardrail
 }
 
 var _ provider.Service[*bedrockruntime.ConverseInput, *bedrockruntime.ConverseOutput] = (*Service)(nil)
 
 func (s *Service) Invoke(ctx context.Context, input *bedrockruntime.ConverseInput) (*bedrockruntime.ConverseOutput, error) {
+	if s.guardrail.ID != "" {
+		input.GuardrailConfig = encodeGuardrail(s.guardrail)
+	}
+
 	return s.api.Converse(ctx, input)
 }
+
+func encodeGuardrail(g Guardrail) *types.GuardrailConfiguration {
+	trace := types.GuardrailTraceDisabled
+	if g.Trace {
+		trace = types.GuardrailTraceEnabled
+	}
+
+	return &types.GuardrailConfiguration{
+		GuardrailIdentifier: aws.String(g.ID),
+		GuardrailVersion:    aws.String(g.Version),
+		Trace:               trace,
+	}
+}
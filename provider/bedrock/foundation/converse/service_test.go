@@ -0,0 +1,54 @@
+//
+// Copyright 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package converse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+)
+
+type runtime struct {
+	input *bedrockruntime.ConverseInput
+}
+
+func (r *runtime) Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+	r.input = params
+	return &bedrockruntime.ConverseOutput{
+		Output: &types.ConverseOutputMemberMessage{
+			Value: types.Message{
+				Role:    types.ConversationRoleAssistant,
+				Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: "ok"}},
+			},
+		},
+		StopReason: types.StopReasonEndTurn,
+	}, nil
+}
+
+func TestServiceGuardrail(t *testing.T) {
+	api := &runtime{}
+	llm, err := New("test-model",
+		WithRuntime(api),
+		WithGuardrail(Guardrail{ID: "gr-1", Version: "DRAFT", Trace: true}),
+	)
+	it.Then(t).Must(it.Nil(err))
+
+	_, err = llm.Prompt(context.Background(), []chatter.Message{chatter.Text("hello")})
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(aws.ToString(api.input.GuardrailConfig.GuardrailIdentifier), "gr-1"),
+		it.Equal(aws.ToString(api.input.GuardrailConfig.GuardrailVersion), "DRAFT"),
+		it.Equal(api.input.GuardrailConfig.Trace, types.GuardrailTraceEnabled),
+	)
+}
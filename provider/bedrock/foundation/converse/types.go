@@ -35,5 +35,5 @@ func New(model string, opt ...Option) (*Converse, error) {
 		}
 	}
 
-	return provider.New(factory(model, c.registry), decoder{}, c), nil
+	return provider.New(factory(model, c.registry), decoder{}, c).WithModel(model), nil
 }
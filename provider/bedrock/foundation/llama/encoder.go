@@ -99,6 +99,11 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+func (codec *encoder) AsCachePoint(chatter.CachePoint) error {
+	// Llama doesn't support prompt caching
+	return nil
+}
+
 func (codec *encoder) Build() *input {
 	codec.req.Prompt = codec.w.String()
 	return &codec.req
@@ -54,5 +54,5 @@ func New(model string, opts ...bedrock.Option) (*Llama, error) {
 		return nil, err
 	}
 
-	return provider.New(factory, decoder{}, service), nil
+	return provider.New(factory, decoder{}, service).WithModel(model), nil
 }
@@ -26,8 +26,12 @@ func (decoder decoder) Decode(response *reply) (*chatter.Reply, error) {
 		reply.Stage = chatter.LLM_ERROR
 	}
 
-	reply.Usage.InputTokens = response.Usage.InputTokens
+	// Bedrock reports input tokens excluding cached ones
+	reply.Usage.InputTokens = response.Usage.InputTokens +
+		response.Usage.CacheReadInputTokenCount + response.Usage.CacheWriteInputTokenCount
 	reply.Usage.ReplyTokens = response.Usage.OutputTokens
+	reply.Usage.CacheReadTokens = response.Usage.CacheReadInputTokenCount
+	reply.Usage.CacheWriteTokens = response.Usage.CacheWriteInputTokenCount
 
 	reply.Content = make([]chatter.Content, 0, len(response.Output.Message.Content))
 	for _, c := range response.Output.Message.Content {
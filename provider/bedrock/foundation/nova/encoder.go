@@ -110,6 +110,22 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+// AsCachePoint places the cache checkpoint after preceding block: the latest
+// message or the system prompt, if there are no messages yet.
+func (codec *encoder) AsCachePoint(chatter.CachePoint) error {
+	block := content{CachePoint: &cachePoint{Type: "default"}}
+
+	switch {
+	case len(codec.req.Messages) > 0:
+		msg := &codec.req.Messages[len(codec.req.Messages)-1]
+		msg.Content = append(msg.Content, block)
+	case len(codec.req.System) > 0:
+		codec.req.System = append(codec.req.System, block)
+	}
+
+	return nil
+}
+
 func (codec *encoder) Build() *input {
 	return &codec.req
 }
@@ -251,3 +251,32 @@ func TestEncoderUnsupportedFeatures(t *testing.T) {
 		"messages": []
 	}`))
 }
+
+func TestEncoderCachePoint(t *testing.T) {
+	f, err := factory()
+	it.Then(t).Must(it.Nil(err))
+
+	it.Then(t).Must(
+		it.Nil(f.AsCachePoint(chatter.CachePoint{})),
+		it.Nil(f.AsStratum(chatter.Stratum("You are a helpful assistant"))),
+		it.Nil(f.AsCachePoint(chatter.CachePoint{})),
+		it.Nil(f.AsText(chatter.Text("Hello"))),
+		it.Nil(f.AsCachePoint(chatter.CachePoint{})),
+	)
+
+	it.Then(t).Should(it.Json(f.Build()).Equiv(`{
+		"system": [
+			{"text": "You are a helpful assistant"},
+			{"cachePoint": {"type": "default"}}
+		],
+		"messages": [
+			{
+				"role": "user",
+				"content": [
+					{"text": "Hello"},
+					{"cachePoint": {"type": "default"}}
+				]
+			}
+		]
+	}`))
+}
@@ -31,9 +31,14 @@ type message struct {
 }
 
 type content struct {
-	Text  string `json:"text,omitempty"`
-	Image any    `json:"image,omitempty"`
-	Video any    `json:"video,omitempty"`
+	Text       string      `json:"text,omitempty"`
+	Image      any         `json:"image,omitempty"`
+	Video      any         `json:"video,omitempty"`
+	CachePoint *cachePoint `json:"cachePoint,omitempty"`
+}
+
+type cachePoint struct {
+	Type string `json:"type"`
 }
 
 type inferenceConfig struct {
@@ -76,7 +81,7 @@ func New(model string, opts ...bedrock.Option) (*Nova, error) {
 		return nil, err
 	}
 
-	return provider.New(factory, decoder{}, service), nil
+	return provider.New(factory, decoder{}, service).WithModel(model), nil
 }
 
 type NovaBatch = batch.Provider[*input, *reply]
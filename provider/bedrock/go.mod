@@ -14,7 +14,7 @@ require (
 	github.com/fogfish/it/v2 v2.2.4
 	github.com/fogfish/opts v0.0.5
 	github.com/fogfish/stream v1.3.0
-	github.com/kshard/chatter v0.11.2
+	github.com/kshard/chatter v0.12.0
 )
 
 require (
@@ -53,3 +53,5 @@ require (
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/tools v0.33.0 // indirect
 )
+
+replace github.com/kshard/chatter => ../..
@@ -9,6 +9,7 @@
 package gemini
 
 import (
+	"encoding/base64"
 	"fmt"
 
 	"github.com/kshard/chatter"
@@ -26,7 +27,18 @@ func (decoder decoder) Decode(bag *genai.GenerateContentResponse) (*chatter.Repl
 
 	content := []chatter.Content{}
 	for _, part := range bag.Candidates[0].Content.Parts {
-		if part.Text != "" {
+		if part.Thought {
+			content = append(content, chatter.Reasoning{
+				Text:      part.Text,
+				Signature: base64.StdEncoding.EncodeToString(part.ThoughtSignature),
+			})
+		} else if part.Text != "" {
+			// signature of reply part is preserved as reasoning without text
+			if len(part.ThoughtSignature) > 0 {
+				content = append(content, chatter.Reasoning{
+					Signature: base64.StdEncoding.EncodeToString(part.ThoughtSignature),
+				})
+			}
 			content = append(content, chatter.Text(part.Text))
 		} else if part.InlineData != nil {
 			content = append(content, &chatter.Binary{
@@ -39,10 +51,22 @@ func (decoder decoder) Decode(bag *genai.GenerateContentResponse) (*chatter.Repl
 	reply := &chatter.Reply{
 		Stage:   chatter.LLM_RETURN,
 		Content: content,
-		Usage: chatter.Usage{
-			InputTokens: int(bag.UsageMetadata.ToolUsePromptTokenCount),
-			ReplyTokens: int(bag.UsageMetadata.CandidatesTokenCount),
-		},
+		Usage:   decodeUsage(bag.UsageMetadata),
 	}
 	return reply, nil
 }
+
+// Gemini reports prompt including cached content, tool results and thoughts
+// are reported separately.
+func decodeUsage(meta *genai.GenerateContentResponseUsageMetadata) chatter.Usage {
+	if meta == nil {
+		return chatter.Usage{}
+	}
+
+	return chatter.Usage{
+		InputTokens:     int(meta.PromptTokenCount + meta.ToolUsePromptTokenCount),
+		ReplyTokens:     int(meta.CandidatesTokenCount + meta.ThoughtsTokenCount),
+		CacheReadTokens: int(meta.CachedContentTokenCount),
+		ReasoningTokens: int(meta.ThoughtsTokenCount),
+	}
+}
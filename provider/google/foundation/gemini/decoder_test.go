@@ -0,0 +1,148 @@
+//
+// Copyright (C) 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package gemini
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"google.golang.org/genai"
+)
+
+func response(parts ...*genai.Part) *genai.GenerateContentResponse {
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{
+			{Content: &genai.Content{Role: genai.RoleModel, Parts: parts}},
+		},
+	}
+}
+
+func TestDecoderNoCandidates(t *testing.T) {
+	_, err := decoder{}.Decode(&genai.GenerateContentResponse{})
+	it.Then(t).ShouldNot(it.Nil(err))
+}
+
+func TestDecoderThoughts(t *testing.T) {
+	sig := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		parts   []*genai.Part
+		content []chatter.Content
+	}{
+		{
+			name:    "text",
+			parts:   []*genai.Part{{Text: "answer"}},
+			content: []chatter.Content{chatter.Text("answer")},
+		},
+		{
+			name: "thought",
+			parts: []*genai.Part{
+				{Text: "thinking", Thought: true, ThoughtSignature: []byte("sig-1")},
+				{Text: "answer"},
+			},
+			content: []chatter.Content{
+				chatter.Reasoning{Text: "thinking", Signature: sig("sig-1")},
+				chatter.Text("answer"),
+			},
+		},
+		{
+			name:  "signed_text",
+			parts: []*genai.Part{{Text: "answer", ThoughtSignature: []byte("sig-2")}},
+			content: []chatter.Content{
+				chatter.Reasoning{Signature: sig("sig-2")},
+				chatter.Text("answer"),
+			},
+		},
+		{
+			name:    "inline_data",
+			parts:   []*genai.Part{{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte{1, 2, 3}}}},
+			content: []chatter.Content{&chatter.Binary{Type: "image/png", Data: []byte{1, 2, 3}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reply, err := decoder{}.Decode(response(test.parts...))
+			it.Then(t).Should(
+				it.Nil(err),
+				it.Equal(reply.Stage, chatter.LLM_RETURN),
+				it.Equiv(reply.Content, test.content),
+			)
+		})
+	}
+}
+
+// thoughts and signatures decoded from the reply are replayed as-is
+func TestDecoderReplayRoundTrip(t *testing.T) {
+	parts := []*genai.Part{
+		{Text: "thinking", Thought: true, ThoughtSignature: []byte("sig-1")},
+		{Text: "answer", ThoughtSignature: []byte("sig-2")},
+	}
+
+	reply, err := decoder{}.Decode(response(parts...))
+	it.Then(t).Must(it.Nil(err))
+
+	f, err := factory("gemini-2.5-pro")()
+	it.Then(t).Must(it.Nil(err))
+	it.Then(t).Must(it.Nil(f.AsReply(reply)))
+
+	it.Then(t).Should(
+		it.Equiv(f.Build().Prompt[0].Parts, parts),
+	)
+}
+
+func TestDecoderUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		meta  *genai.GenerateContentResponseUsageMetadata
+		usage chatter.Usage
+	}{
+		{
+			name:  "no_metadata",
+			meta:  nil,
+			usage: chatter.Usage{},
+		},
+		{
+			name:  "standard_tokens",
+			meta:  &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 50},
+			usage: chatter.Usage{InputTokens: 100, ReplyTokens: 50},
+		},
+		{
+			name:  "tool_use_prompt",
+			meta:  &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, ToolUsePromptTokenCount: 20, CandidatesTokenCount: 50},
+			usage: chatter.Usage{InputTokens: 120, ReplyTokens: 50},
+		},
+		{
+			name:  "cached_content",
+			meta:  &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 2000, CachedContentTokenCount: 1024, CandidatesTokenCount: 50},
+			usage: chatter.Usage{InputTokens: 2000, ReplyTokens: 50, CacheReadTokens: 1024},
+		},
+		{
+			name:  "thoughts",
+			meta:  &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 50, ThoughtsTokenCount: 640},
+			usage: chatter.Usage{InputTokens: 100, ReplyTokens: 690, ReasoningTokens: 640},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bag := response(&genai.Part{Text: "ok"})
+			bag.UsageMetadata = test.meta
+
+			reply, err := decoder{}.Decode(bag)
+			it.Then(t).Should(
+				it.Nil(err),
+				it.Equal(reply.Usage, test.usage),
+			)
+		})
+	}
+}
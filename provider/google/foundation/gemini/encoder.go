@@ -9,6 +9,8 @@
 package gemini
 
 import (
+	"encoding/base64"
+
 	"github.com/kshard/chatter"
 	"github.com/kshard/chatter/aio/provider"
 	"google.golang.org/genai"
@@ -53,6 +55,14 @@ func (codec *encoder) WithInferrer(inf provider.Inferrer) {
 	if inf.StopSequences != nil {
 		codec.req.Params.StopSequences = inf.StopSequences
 	}
+
+	if _, budget := inf.Reasoning(); budget > 0 {
+		b := int32(budget)
+		codec.req.Params.ThinkingConfig = &genai.ThinkingConfig{
+			IncludeThoughts: true,
+			ThinkingBudget:  &b,
+		}
+	}
 }
 
 func (codec *encoder) WithCommand(cmd chatter.Cmd) {
@@ -95,15 +105,43 @@ func (codec *encoder) AsAnswer(answer *chatter.Answer) error {
 }
 
 func (codec *encoder) AsReply(reply *chatter.Reply) error {
+	parts := []*genai.Part{}
+	signature := []byte(nil)
+
+	for _, block := range reply.Content {
+		switch v := block.(type) {
+		case chatter.Text:
+			parts = append(parts, &genai.Part{Text: string(v), ThoughtSignature: signature})
+			signature = nil
+		case chatter.Reasoning:
+			sig, err := base64.StdEncoding.DecodeString(v.Signature)
+			if err != nil {
+				return err
+			}
+			// signature of the reply part is carried by reasoning without text
+			if v.Text == "" {
+				signature = sig
+				continue
+			}
+			parts = append(parts, &genai.Part{Text: v.Text, Thought: true, ThoughtSignature: sig})
+		}
+	}
+
 	codec.req.Prompt = append(codec.req.Prompt,
 		&genai.Content{
 			Role:  genai.RoleModel,
-			Parts: []*genai.Part{{Text: reply.String()}},
+			Parts: parts,
 		},
 	)
 	return nil
 }
 
+func (codec *encoder) AsCachePoint(chatter.CachePoint) error {
+	// Gemini caches prompt prefixes implicitly, explicit caching requires
+	// cached content management, which is outside of the request
+	return nil
+}
+
 func (codec *encoder) Build() *input {
 	return &codec.req
 }
@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package gemini
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio/provider"
+	"google.golang.org/genai"
+)
+
+func TestEncoderInferenceConfiguration(t *testing.T) {
+	f, err := factory("gemini-2.5-flash")()
+	it.Then(t).Must(it.Nil(err))
+
+	f.WithInferrer(provider.Inferrer{
+		Temperature:   0.7,
+		TopP:          0.9,
+		MaxTokens:     512,
+		StopSequences: []string{"STOP"},
+	})
+
+	// parameters are passed to the service as generation config
+	params := f.Build().Params
+	it.Then(t).Should(
+		it.Equal(*params.Temperature, float32(0.7)),
+		it.Equal(*params.TopP, float32(0.9)),
+		it.Equal(params.MaxOutputTokens, 512),
+		it.Seq(params.StopSequences).Equal("STOP"),
+		it.True(params.ThinkingConfig == nil),
+	)
+}
+
+func TestEncoderThinkingConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		inferrer provider.Inferrer
+		budget   int32
+	}{
+		{
+			name:     "explicit_budget",
+			inferrer: provider.Inferrer{ReasoningBudget: 2048},
+			budget:   2048,
+		},
+		{
+			name:     "low_effort",
+			inferrer: provider.Inferrer{ReasoningEffort: string(chatter.ReasoningLow)},
+			budget:   1024,
+		},
+		{
+			name:     "high_effort",
+			inferrer: provider.Inferrer{ReasoningEffort: string(chatter.ReasoningHigh)},
+			budget:   16384,
+		},
+		{
+			name:     "budget_overrides_effort",
+			inferrer: provider.Inferrer{ReasoningEffort: string(chatter.ReasoningHigh), ReasoningBudget: 512},
+			budget:   512,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := factory("gemini-2.5-pro")()
+			it.Then(t).Must(it.Nil(err))
+
+			f.WithInferrer(test.inferrer)
+
+			cfg := f.Build().Params.ThinkingConfig
+			it.Then(t).Must(it.True(cfg != nil))
+			it.Then(t).Should(
+				it.True(cfg.IncludeThoughts),
+				it.Equal(*cfg.ThinkingBudget, test.budget),
+			)
+		})
+	}
+}
+
+func TestEncoderReplayThoughtSignature(t *testing.T) {
+	sig := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		content []chatter.Content
+		parts   []*genai.Part
+	}{
+		{
+			name:    "text",
+			content: []chatter.Content{chatter.Text("answer")},
+			parts:   []*genai.Part{{Text: "answer"}},
+		},
+		{
+			name: "thought",
+			content: []chatter.Content{
+				chatter.Reasoning{Text: "thinking", Signature: sig("sig-1")},
+				chatter.Text("answer"),
+			},
+			parts: []*genai.Part{
+				{Text: "thinking", Thought: true, ThoughtSignature: []byte("sig-1")},
+				{Text: "answer"},
+			},
+		},
+		{
+			name: "signed_text",
+			content: []chatter.Content{
+				chatter.Reasoning{Signature: sig("sig-2")},
+				chatter.Text("answer"),
+				chatter.Text("more"),
+			},
+			parts: []*genai.Part{
+				{Text: "answer", ThoughtSignature: []byte("sig-2")},
+				{Text: "more"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := factory("gemini-2.5-pro")()
+			it.Then(t).Must(it.Nil(err))
+
+			err = f.AsReply(&chatter.Reply{Stage: chatter.LLM_RETURN, Content: test.content})
+			it.Then(t).Must(it.Nil(err))
+
+			prompt := f.Build().Prompt
+			it.Then(t).Should(
+				it.Equal(len(prompt), 1),
+				it.Equal(prompt[0].Role, genai.RoleModel),
+				it.Equiv(prompt[0].Parts, test.parts),
+			)
+		})
+	}
+
+	t.Run("invalid_signature", func(t *testing.T) {
+		f, err := factory("gemini-2.5-pro")()
+		it.Then(t).Must(it.Nil(err))
+
+		err = f.AsReply(&chatter.Reply{Content: []chatter.Content{chatter.Reasoning{Signature: "%%%"}}})
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+}
@@ -38,7 +38,7 @@ func New(model string, opt Config) (*Gemini, error) {
 
 	c := &Service{api: api}
 
-	return provider.New(factory(model), decoder{}, c), nil
+	return provider.New(factory(model), decoder{}, c).WithModel(model), nil
 }
 
 //------------------------------------------------------------------------------
@@ -46,5 +46,5 @@ func New(model string, opt Config) (*Gemini, error) {
 var _ provider.Service[*input, *genai.GenerateContentResponse] = (*Service)(nil)
 
 func (s *Service) Invoke(ctx context.Context, input *input) (*genai.GenerateContentResponse, error) {
-	return s.api.Models.GenerateContent(ctx, input.Model, input.Prompt, &genai.GenerateContentConfig{})
+	return s.api.Models.GenerateContent(ctx, input.Model, input.Prompt, &input.Params)
 }
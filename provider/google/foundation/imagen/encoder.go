@@ -59,6 +59,11 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+func (codec *encoder) AsCachePoint(chatter.CachePoint) error {
+	// Image generation doesn't support prompt caching
+	return nil
+}
+
 func (codec *encoder) Build() *input {
 	return &codec.req
 }
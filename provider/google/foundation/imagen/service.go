@@ -44,7 +44,7 @@ func New(model string, opt Config) (*Imagen, error) {
 
 	c := &Service{api: api}
 
-	return provider.New(factory(model), decoder{}, c), nil
+	return provider.New(factory(model), decoder{}, c).WithModel(model), nil
 }
 
 //------------------------------------------------------------------------------
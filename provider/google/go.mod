@@ -3,7 +3,8 @@ module github.com/kshard/chatter/provider/google
 go 1.25.0
 
 require (
-	github.com/kshard/chatter v0.11.2
+	github.com/fogfish/it/v2 v2.2.4
+	github.com/kshard/chatter v0.12.0
 	google.golang.org/genai v1.34.0
 )
 
@@ -26,3 +27,5 @@ require (
 	google.golang.org/grpc v1.66.2 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
 )
+
+replace github.com/kshard/chatter => ../..
@@ -54,5 +54,5 @@ func New(model string, dimensions int, opts ...openai.Option) (*Text, error) {
 		return nil, err
 	}
 
-	return provider.New(factory(model, dimensions), decoder{}, service), nil
+	return provider.New(factory(model, dimensions), decoder{}, service).WithModel(model), nil
 }
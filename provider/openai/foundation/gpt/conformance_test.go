@@ -0,0 +1,98 @@
+//
+// Copyright (C) 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package gpt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/aio/provider"
+	"github.com/kshard/chatter/aio/provider/providertest"
+)
+
+func TestConformance(t *testing.T) {
+	report := providertest.Run(t, providertest.Kit[*input, *reply]{
+		Factory:  factory("gpt-4o"),
+		Decoder:  decoder{},
+		Inspect:  inspect,
+		Response: response,
+	})
+
+	report.Expect(t,
+		providertest.Stratum,
+		providertest.MultiTurn,
+		providertest.ReplyReplay,
+		providertest.Temperature,
+		providertest.TopP,
+		providertest.MaxTokens,
+		providertest.ToolInvoke,
+		providertest.StopReturn,
+		providertest.StopIncomplete,
+		providertest.StopInvoke,
+		providertest.Usage,
+		providertest.Reasoning,
+		providertest.ReasoningOption,
+	)
+}
+
+func inspect(req *input) providertest.Request {
+	r := providertest.Request{
+		Inferrer: provider.Inferrer{
+			Temperature:     req.Temperature,
+			TopP:            req.TopP,
+			MaxTokens:       req.MaxTokens,
+			ReasoningEffort: req.Reasoning,
+		},
+	}
+
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			r.System += msg.Content
+			continue
+		}
+		r.Messages = append(r.Messages, providertest.Message{Role: msg.Role, Text: msg.Content})
+	}
+
+	return r
+}
+
+func response(r providertest.Response) (*reply, error) {
+	msg := message{Role: "assistant", Content: r.Text}
+	for _, reasoning := range r.Reasoning {
+		msg.ReasoningContent += reasoning.Text
+	}
+	for _, invoke := range r.Invoke {
+		msg.ToolCalls = append(msg.ToolCalls, toolCall{
+			ID:       invoke.Args.ID,
+			Type:     "function",
+			Function: function{Name: invoke.Cmd, Arguments: string(invoke.Args.Value)},
+		})
+	}
+
+	var reason string
+	switch r.Stage {
+	case chatter.LLM_RETURN:
+		reason = "stop"
+	case chatter.LLM_INCOMPLETE:
+		reason = "length"
+	case chatter.LLM_INVOKE:
+		reason = "tool_calls"
+	default:
+		return nil, fmt.Errorf("unsupported stage %s", r.Stage)
+	}
+
+	return &reply{
+		Choices: []choice{{Message: msg, FinishReason: reason}},
+		Usage: usage{
+			PromptTokens: r.Usage.InputTokens,
+			OutputTokens: r.Usage.ReplyTokens,
+		},
+	}, nil
+}
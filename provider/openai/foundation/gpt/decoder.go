@@ -8,18 +8,54 @@
 
 package gpt
 
-import "github.com/kshard/chatter"
+import (
+	"encoding/json"
+
+	"github.com/kshard/chatter"
+)
 
 func (decoder decoder) Decode(bag *reply) (*chatter.Reply, error) {
+	msg := bag.Choices[0].Message
+
+	content := []chatter.Content{}
+	if msg.ReasoningContent != "" {
+		content = append(content, chatter.Reasoning{Text: msg.ReasoningContent})
+	}
+	if msg.Content != "" || len(msg.ToolCalls) == 0 {
+		content = append(content, chatter.Text(msg.Content))
+	}
+	for _, call := range msg.ToolCalls {
+		content = append(content, chatter.Invoke{
+			Cmd: call.Function.Name,
+			Args: chatter.Json{
+				ID:    call.ID,
+				Value: json.RawMessage(call.Function.Arguments),
+			},
+		})
+	}
+
 	reply := &chatter.Reply{
-		Stage: chatter.LLM_RETURN,
-		Content: []chatter.Content{
-			chatter.Text(bag.Choices[0].Message.Content),
-		},
+		Stage:   decodeStage(bag.Choices[0].FinishReason),
+		Content: content,
 		Usage: chatter.Usage{
-			InputTokens: bag.Usage.PromptTokens,
-			ReplyTokens: bag.Usage.OutputTokens,
+			InputTokens:     bag.Usage.PromptTokens,
+			ReplyTokens:     bag.Usage.OutputTokens,
+			CacheReadTokens: bag.Usage.PromptTokensDetails.CachedTokens,
+			ReasoningTokens: bag.Usage.OutputTokensDetails.ReasoningTokens,
 		},
 	}
 	return reply, nil
 }
+
+func decodeStage(reason string) chatter.Stage {
+	switch reason {
+	case "length":
+		return chatter.LLM_INCOMPLETE
+	case "tool_calls":
+		return chatter.LLM_INVOKE
+	case "content_filter":
+		return chatter.LLM_GUARDRAIL
+	default:
+		return chatter.LLM_RETURN
+	}
+}
@@ -52,6 +52,51 @@ func TestDecoderBasicReplyDecoding(t *testing.T) {
 	)
 }
 
+func TestDecoderDetailedUsage(t *testing.T) {
+	input := &reply{
+		ID:      "chatcmpl-test-124",
+		Choices: []choice{{Message: message{Role: "assistant", Content: "ok"}}},
+		Usage: usage{
+			PromptTokens:        1500,
+			OutputTokens:        700,
+			UsedTokens:          2200,
+			PromptTokensDetails: promptTokensDetails{CachedTokens: 1024},
+			OutputTokensDetails: outputTokensDetails{ReasoningTokens: 640},
+		},
+	}
+
+	result, err := decoder{}.Decode(input)
+
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Json(result.Usage).Equiv(`{
+			"inputTokens": 1500,
+			"replyTokens": 700,
+			"cacheReadTokens": 1024,
+			"reasoningTokens": 640
+		}`),
+	)
+}
+
+func TestDecoderReasoningContent(t *testing.T) {
+	input := &reply{
+		ID: "chatcmpl-test-125",
+		Choices: []choice{{Message: message{
+			Role:             "assistant",
+			Content:          "4",
+			ReasoningContent: "The user asks for a sum.",
+		}}},
+	}
+
+	result, err := decoder{}.Decode(input)
+
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Equal(result.String(), "4"),
+		it.Equal(result.Reasoning(), "The user asks for a sum."),
+	)
+}
+
 func TestDecoderComplexResponseContent(t *testing.T) {
 	input := &reply{
 		ID: "chatcmpl-code-review-456",
@@ -486,3 +531,54 @@ func TestDecoderProtocolCompatibility(t *testing.T) {
 		)
 	})
 }
+
+func TestDecoderFinishReason(t *testing.T) {
+	for reason, stage := range map[string]chatter.Stage{
+		"":               chatter.LLM_RETURN,
+		"stop":           chatter.LLM_RETURN,
+		"length":         chatter.LLM_INCOMPLETE,
+		"tool_calls":     chatter.LLM_INVOKE,
+		"content_filter": chatter.LLM_GUARDRAIL,
+	} {
+		t.Run(reason, func(t *testing.T) {
+			result, err := decoder{}.Decode(&reply{
+				Choices: []choice{{Message: message{Role: "assistant", Content: "ok"}, FinishReason: reason}},
+			})
+
+			it.Then(t).Should(
+				it.Nil(err),
+				it.Equal(result.Stage, stage),
+			)
+		})
+	}
+}
+
+func TestDecoderToolCalls(t *testing.T) {
+	result, err := decoder{}.Decode(&reply{
+		Choices: []choice{
+			{
+				Message: message{
+					Role: "assistant",
+					ToolCalls: []toolCall{
+						{ID: "call_1", Type: "function", Function: function{Name: "weather", Arguments: `{"city":"Helsinki"}`}},
+					},
+				},
+				FinishReason: "tool_calls",
+			},
+		},
+	})
+
+	it.Then(t).Should(
+		it.Nil(err),
+		it.Json(result).Equiv(`{
+			"stage": "invoke",
+			"usage": {},
+			"content": [
+				{
+					"name": "weather",
+					"args": {"id": "call_1", "bag": {"city": "Helsinki"}}
+				}
+			]
+		}`),
+	)
+}
@@ -33,6 +33,9 @@ func (codec *encoder) WithInferrer(inf provider.Inferrer) {
 	if inf.MaxTokens > 0 {
 		codec.req.MaxTokens = inf.MaxTokens
 	}
+	if effort, _ := inf.Reasoning(); effort != "" {
+		codec.req.Reasoning = effort
+	}
 }
 
 func (codec *encoder) WithCommand(cmd chatter.Cmd) {
@@ -68,6 +71,11 @@ func (codec *encoder) AsReply(reply *chatter.Reply) error {
 	return nil
 }
 
+func (codec *encoder) AsCachePoint(chatter.CachePoint) error {
+	// OpenAI caches prompt prefixes automatically
+	return nil
+}
+
 func (codec *encoder) Build() *input {
 	return &codec.req
 }
@@ -46,6 +46,19 @@ func TestEncoderInferenceConfiguration(t *testing.T) {
 	}`))
 }
 
+func TestEncoderReasoningEffort(t *testing.T) {
+	f, err := factory("o4-mini")()
+	it.Then(t).Must(it.Nil(err))
+
+	f.WithInferrer(provider.Inferrer{ReasoningBudget: 16384})
+
+	it.Then(t).Should(it.Json(f.Build()).Equiv(`{
+		"model": "o4-mini",
+		"messages": [],
+		"reasoning_effort": "high"
+	}`))
+}
+
 func TestEncoderSystemMessage(t *testing.T) {
 	f, err := factory("gpt-4")()
 	it.Then(t).Must(it.Nil(err))
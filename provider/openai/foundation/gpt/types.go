@@ -22,11 +22,28 @@ type input struct {
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	Temperature float64   `json:"temperature,omitempty"`
 	TopP        float64   `json:"top_p,omitempty"`
+	Reasoning   string    `json:"reasoning_effort,omitempty"`
 }
 
 type message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// reasoning returned by OpenAI compatible servers (e.g. DeepSeek, vLLM),
+	// it is not replayed within the conversation.
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// tools requested by the model, they are not replayed within the conversation.
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+}
+
+type toolCall struct {
+	ID       string   `json:"id"`
+	Type     string   `json:"type"`
+	Function function `json:"function"`
+}
+
+type function struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 type reply struct {
@@ -36,13 +53,24 @@ type reply struct {
 }
 
 type choice struct {
-	Message message `json:"message"`
+	Message      message `json:"message"`
+	FinishReason string  `json:"finish_reason,omitempty"`
 }
 
 type usage struct {
-	PromptTokens int `json:"prompt_tokens"`
-	OutputTokens int `json:"completion_tokens"`
-	UsedTokens   int `json:"total_tokens"`
+	PromptTokens        int                 `json:"prompt_tokens"`
+	OutputTokens        int                 `json:"completion_tokens"`
+	UsedTokens          int                 `json:"total_tokens"`
+	PromptTokensDetails promptTokensDetails `json:"prompt_tokens_details,omitempty"`
+	OutputTokensDetails outputTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+type promptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens,omitempty"`
+}
+
+type outputTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
 }
 
 type encoder struct{ req input }
@@ -57,7 +85,7 @@ func New(model string, opt ...openai.Option) (*GPT, error) {
 		return nil, err
 	}
 
-	return provider.New(factory(model), decoder{}, service), nil
+	return provider.New(factory(model), decoder{}, service).WithModel(model), nil
 }
 
 func Must[T any](api T, err error) T {
@@ -8,7 +8,7 @@ require (
 	github.com/fogfish/logger/x/xlog v0.0.1
 	github.com/fogfish/opts v0.0.5
 	github.com/jdxcode/netrc v1.0.0
-	github.com/kshard/chatter v0.11.2
+	github.com/kshard/chatter v0.12.0
 )
 
 require (
@@ -20,3 +20,5 @@ require (
 	github.com/google/go-cmp v0.7.0 // indirect
 	golang.org/x/net v0.52.0 // indirect
 )
+
+replace github.com/kshard/chatter => ../..
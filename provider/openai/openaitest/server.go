@@ -0,0 +1,335 @@
+//
+// Copyright (C) 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package openaitest implements the fake of OpenAI compatible API for
+// integration tests without network. The server implements chat completions
+// and embeddings with deterministic or scripted responses, injects errors and
+// captures requests for assertions.
+//
+//	srv := openaitest.New().WithSecret("sk-test")
+//	defer srv.Close()
+//
+//	srv.Reply("Hello!").Fail(http.StatusTooManyRequests)
+//
+//	llm, err := gpt.New("gpt-4o",
+//		openai.WithHost(srv.URL),
+//		openai.WithSecret("sk-test"),
+//	)
+package openaitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PathChat       = "/v1/chat/completions"
+	PathEmbeddings = "/v1/embeddings"
+)
+
+// Request captured by the server
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Decode the body of captured request
+func (r Request) Decode(v any) error { return json.Unmarshal(r.Body, v) }
+
+// fault injected into the response
+type fault struct {
+	status int
+	body   string
+}
+
+// Server is the fake of OpenAI compatible API. Unless scripted, the chat
+// completion echoes the latest message and embeddings are derived from
+// the hash of input text.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	secret     string
+	delay      time.Duration
+	dimensions int
+	replies    []string
+	faults     []fault
+	requests   []Request
+}
+
+// Starts new fake server, the caller must Close it.
+func New() *Server {
+	s := &Server{dimensions: 8}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Require bearer token, requests without it fails with 401.
+func (s *Server) WithSecret(secret string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.secret = secret
+	return s
+}
+
+// Delay every response, used to test timeouts.
+func (s *Server) WithDelay(d time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delay = d
+	return s
+}
+
+// Set default dimension of embeddings, the request might override it.
+func (s *Server) WithDimensions(n int) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dimensions = n
+	return s
+}
+
+// Reply appends scripted chat completions, each is used once in order.
+func (s *Server) Reply(text ...string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies = append(s.replies, text...)
+	return s
+}
+
+// Fail injects the error response with OpenAI error body into the next request.
+func (s *Server) Fail(status int) *Server {
+	kind, text := "server_error", http.StatusText(status)
+	switch {
+	case status == http.StatusTooManyRequests:
+		kind, text = "rate_limit_exceeded", "Rate limit reached, please try again later."
+	case status < 500:
+		kind = "invalid_request_error"
+	}
+
+	return s.FailWith(status, errorBody(kind, text))
+}
+
+// FailWith injects arbitrary response into the next request.
+func (s *Server) FailWith(status int, body string) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, fault{status: status, body: body})
+	return s
+}
+
+// Malformed injects 200 OK response with malformed JSON into the next request.
+func (s *Server) Malformed() *Server {
+	return s.FailWith(http.StatusOK, `{"id": "chatcmpl-malformed", "choices": [`)
+}
+
+// Requests captured by the server
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// Latest request captured by the server
+func (s *Server) Latest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return Request{}, false
+	}
+	return s.requests[len(s.requests)-1], true
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	delay, secret := s.delay, s.secret
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if secret != "" && r.Header.Get("Authorization") != "Bearer "+secret {
+		write(w, http.StatusUnauthorized, errorBody("invalid_request_error", "Incorrect API key provided."))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		write(w, http.StatusMethodNotAllowed, errorBody("invalid_request_error", "Method not allowed."))
+		return
+	}
+
+	s.mu.Lock()
+	if len(s.faults) > 0 {
+		f := s.faults[0]
+		s.faults = s.faults[1:]
+		s.mu.Unlock()
+		write(w, f.status, f.body)
+		return
+	}
+	s.mu.Unlock()
+
+	var reply any
+	switch r.URL.Path {
+	case PathChat:
+		reply, err = s.chat(body)
+	case PathEmbeddings:
+		reply, err = s.embeddings(body)
+	default:
+		write(w, http.StatusNotFound, errorBody("invalid_request_error", "Unknown url "+r.URL.Path))
+		return
+	}
+
+	if err != nil {
+		write(w, http.StatusBadRequest, errorBody("invalid_request_error", err.Error()))
+		return
+	}
+
+	b, err := json.Marshal(reply)
+	if err != nil {
+		write(w, http.StatusInternalServerError, errorBody("server_error", err.Error()))
+		return
+	}
+
+	write(w, http.StatusOK, string(b))
+}
+
+func write(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func errorBody(kind, text string) string {
+	body, _ := json.Marshal(map[string]any{
+		"error": map[string]any{"message": text, "type": kind, "code": nil},
+	})
+	return string(body)
+}
+
+//------------------------------------------------------------------------------
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (s *Server) chat(body []byte) (any, error) {
+	var req struct {
+		Model    string    `json:"model"`
+		Messages []message `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages are required")
+	}
+
+	s.mu.Lock()
+	text := ""
+	if len(s.replies) > 0 {
+		text = s.replies[0]
+		s.replies = s.replies[1:]
+	} else {
+		text = "echo: " + req.Messages[len(req.Messages)-1].Content
+	}
+	s.mu.Unlock()
+
+	prompt := 0
+	for _, msg := range req.Messages {
+		prompt += len(strings.Fields(msg.Content))
+	}
+	output := len(strings.Fields(text))
+
+	return map[string]any{
+		"id":      "chatcmpl-fake",
+		"object":  "chat.completion",
+		"model":   req.Model,
+		"choices": []any{map[string]any{"index": 0, "message": message{Role: "assistant", Content: text}, "finish_reason": "stop"}},
+		"usage":   map[string]any{"prompt_tokens": prompt, "completion_tokens": output, "total_tokens": prompt + output},
+	}, nil
+}
+
+func (s *Server) embeddings(body []byte) (any, error) {
+	var req struct {
+		Model      string `json:"model"`
+		Input      string `json:"input"`
+		Dimensions int    `json:"dimensions"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	n := req.Dimensions
+	if n == 0 {
+		s.mu.Lock()
+		n = s.dimensions
+		s.mu.Unlock()
+	}
+
+	tokens := len(strings.Fields(req.Input))
+
+	return map[string]any{
+		"object": "list",
+		"model":  req.Model,
+		"data":   []any{map[string]any{"object": "embedding", "index": 0, "embedding": Embedding(req.Input, n)}},
+		"usage":  map[string]any{"prompt_tokens": tokens, "total_tokens": tokens},
+	}, nil
+}
+
+// Embedding returns deterministic unit vector of the text, as produced by the server.
+func Embedding(text string, n int) []float32 {
+	vec := make([]float32, n)
+	norm := 0.0
+	for i := range vec {
+		h := fnv.New32a()
+		fmt.Fprintf(h, "%d:%s", i, text)
+		v := float64(h.Sum32())/math.MaxUint32*2 - 1
+		vec[i] = float32(v)
+		norm += v * v
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range vec {
+		vec[i] = float32(float64(vec[i]) / norm)
+	}
+	return vec
+}
@@ -10,7 +10,9 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os/user"
 	"path/filepath"
 
@@ -101,7 +103,7 @@ func (s *Service[A, B]) Invoke(ctx context.Context, input A) (B, error) {
 			ø.ContentType.JSON,
 			ø.Send(input),
 
-			ƒ.Status.OK,
+			status,
 			ƒ.ContentType.JSON,
 		),
 	)
@@ -111,3 +113,45 @@ func (s *Service[A, B]) Invoke(ctx context.Context, input A) (B, error) {
 
 	return *bag, nil
 }
+
+// Error returned by OpenAI API, the error body is preserved for the client.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Type       string `json:"type,omitempty"`
+	Code       any    `json:"code,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("openai api failed with %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("openai api failed with %d %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// status checks the response is 200 OK, otherwise decodes the error body
+func status(cat *http.Context) error {
+	if err := cat.Unsafe(); err != nil {
+		return err
+	}
+
+	if cat.Response.StatusCode == 200 {
+		return nil
+	}
+	defer cat.Response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(cat.Response.Body, 64*1024))
+	if err != nil {
+		return err
+	}
+
+	var bag struct {
+		Error *Error `json:"error"`
+	}
+	if err := json.Unmarshal(body, &bag); err != nil || bag.Error == nil {
+		return &Error{StatusCode: cat.Response.StatusCode, Message: string(body)}
+	}
+
+	bag.Error.StatusCode = cat.Response.StatusCode
+	return bag.Error
+}
@@ -0,0 +1,171 @@
+//
+// Copyright (C) 2024 - 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package openai_test
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/fogfish/gurl/v2/http"
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/provider/openai"
+	"github.com/kshard/chatter/provider/openai/embedding/text2vec"
+	"github.com/kshard/chatter/provider/openai/foundation/gpt"
+	"github.com/kshard/chatter/provider/openai/openaitest"
+)
+
+func TestChat(t *testing.T) {
+	srv := openaitest.New().WithSecret("sk-test")
+	defer srv.Close()
+
+	llm, err := gpt.New("gpt-4o",
+		openai.WithHost(srv.URL),
+		openai.WithSecret("sk-test"),
+	)
+	it.Then(t).Must(it.Nil(err))
+
+	t.Run("Echo", func(t *testing.T) {
+		reply, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("Hello World")})
+
+		req, _ := srv.Latest()
+		var body struct {
+			Model string `json:"model"`
+		}
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(reply.String(), "echo: Hello World"),
+			it.Equal(reply.Usage.InputTokens, 2),
+			it.Equal(req.Method, "POST"),
+			it.Equal(req.Path, openaitest.PathChat),
+			it.Equal(req.Header.Get("Authorization"), "Bearer sk-test"),
+			it.Equal(req.Header.Get("Content-Type"), "application/json"),
+			it.Nil(req.Decode(&body)),
+			it.Equal(body.Model, "gpt-4o"),
+		)
+	})
+
+	t.Run("Scripted", func(t *testing.T) {
+		srv.Reply("first", "second")
+
+		a, errA := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("a")})
+		b, errB := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("b")})
+
+		it.Then(t).Should(
+			it.Nil(errA),
+			it.Nil(errB),
+			it.Equal(a.String(), "first"),
+			it.Equal(b.String(), "second"),
+		)
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		srv.Fail(nethttp.StatusTooManyRequests)
+
+		_, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("a")})
+
+		var e *openai.Error
+		it.Then(t).Must(it.True(errors.As(err, &e)))
+		it.Then(t).Should(
+			it.Equal(e.StatusCode, nethttp.StatusTooManyRequests),
+			it.Equal(e.Type, "rate_limit_exceeded"),
+			it.Error(struct{}{}, err).Contain("Rate limit reached"),
+		)
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		srv.Fail(nethttp.StatusInternalServerError)
+
+		_, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("a")})
+
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("500 server_error"),
+		)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		srv.Malformed()
+
+		_, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("a")})
+
+		it.Then(t).ShouldNot(it.Nil(err))
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		llm, err := gpt.New("gpt-4o",
+			openai.WithHost(srv.URL),
+			openai.WithSecret("sk-invalid"),
+		)
+		it.Then(t).Must(it.Nil(err))
+
+		_, err = llm.Prompt(context.Background(), []chatter.Message{chatter.Text("a")})
+
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("401 invalid_request_error"),
+		)
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	srv := openaitest.New().WithDelay(500 * time.Millisecond)
+	defer srv.Close()
+
+	t.Run("Context", func(t *testing.T) {
+		llm, err := gpt.New("gpt-4o", openai.WithHost(srv.URL))
+		it.Then(t).Must(it.Nil(err))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = llm.Prompt(ctx, []chatter.Message{chatter.Text("a")})
+
+		it.Then(t).Should(
+			it.True(errors.Is(err, context.DeadlineExceeded)),
+		)
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		llm, err := gpt.New("gpt-4o",
+			openai.WithHost(srv.URL),
+			openai.WithHTTP(
+				http.WithClient(&nethttp.Client{Timeout: 50 * time.Millisecond}),
+			),
+		)
+		it.Then(t).Must(it.Nil(err))
+
+		_, err = llm.Prompt(context.Background(), []chatter.Message{chatter.Text("a")})
+
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("Client.Timeout"),
+		)
+	})
+}
+
+func TestEmbeddings(t *testing.T) {
+	srv := openaitest.New()
+	defer srv.Close()
+
+	llm, err := text2vec.New("text-embedding-3-small", 4, openai.WithHost(srv.URL))
+	it.Then(t).Must(it.Nil(err))
+
+	reply, err := llm.Prompt(context.Background(), []chatter.Message{chatter.Text("Hello World")})
+	it.Then(t).Must(it.Nil(err))
+
+	req, _ := srv.Latest()
+
+	it.Then(t).Should(
+		it.Equal(req.Path, openaitest.PathEmbeddings),
+		it.Equal(len(reply.Content), 1),
+		it.Seq(reply.Content[0].(chatter.Vector)).Equal(openaitest.Embedding("Hello World", 4)...),
+	)
+}
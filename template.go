@@ -0,0 +1,260 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Template defines the [Prompt] once with named placeholders, using syntax of
+// text/template, and renders it with values. The values are either a map or
+// a struct, missing variables are reported as errors.
+//
+//	tmpl := chatter.NewTemplate().
+//		WithTask("Translate the text to {{.Lang}}").
+//		WithRules("Strictly adhere to the following requirements",
+//			"Use {{.Tone}} tone",
+//		).
+//		WithInput("Text to translate", "{{.Text}}")
+//
+//	prompt, err := tmpl.Render(map[string]any{
+//		"Lang": "Finnish", "Tone": "formal", "Text": "Hello World",
+//	})
+type Template struct {
+	task   *template.Template
+	blocks []templateBlock
+	err    error
+}
+
+// content block of the template, the kind defines the block type
+type templateBlock struct {
+	kind string
+	note *template.Template
+	text []*template.Template
+}
+
+const (
+	blockGuide    = "guide"
+	blockRules    = "rules"
+	blockFeedback = "feedback"
+	blockExample  = "example"
+	blockContext  = "context"
+	blockInput    = "input"
+	blockBlob     = "blob"
+)
+
+func NewTemplate() *Template {
+	return &Template{}
+}
+
+// Err returns the first error of the template definition.
+func (t *Template) Err() error { return t.err }
+
+func (t *Template) parse(name, text string) *template.Template {
+	if t.err != nil {
+		return nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		t.err = fmt.Errorf("invalid template: %w", err)
+		return nil
+	}
+
+	return tmpl
+}
+
+func (t *Template) block(kind, note string, text ...string) *Template {
+	name := fmt.Sprintf("%s[%d]", kind, len(t.blocks))
+
+	b := templateBlock{
+		kind: kind,
+		note: t.parse(name, note),
+		text: make([]*template.Template, len(text)),
+	}
+	for i, x := range text {
+		b.text[i] = t.parse(fmt.Sprintf("%s.%d", name, i), x)
+	}
+
+	t.blocks = append(t.blocks, b)
+	return t
+}
+
+// The task is a summary of what you want the prompt to do.
+func (t *Template) WithTask(task string) *Template {
+	t.task = t.parse("task", task)
+	return t
+}
+
+// Guide LLM on how to complete the task.
+func (t *Template) WithGuide(note string, text ...string) *Template {
+	return t.block(blockGuide, note, text...)
+}
+
+// Requirements of the task.
+func (t *Template) WithRules(note string, text ...string) *Template {
+	return t.block(blockRules, note, text...)
+}
+
+// Give the feedback to LLM on previous completion of the task.
+func (t *Template) WithFeedback(note string, text ...string) *Template {
+	return t.block(blockFeedback, note, text...)
+}
+
+// Give examples to LLM about input data and expected outcomes.
+func (t *Template) WithExample(input, reply string) *Template {
+	return t.block(blockExample, input, reply)
+}
+
+// Additional information required to complete the task.
+func (t *Template) WithContext(note string, text ...string) *Template {
+	return t.block(blockContext, note, text...)
+}
+
+// Input data required to complete the task.
+func (t *Template) WithInput(note string, text ...string) *Template {
+	return t.block(blockInput, note, text...)
+}
+
+// Blob unformatted input data required to complete the task.
+func (t *Template) WithBlob(note string, text string) *Template {
+	return t.block(blockBlob, note, text)
+}
+
+// Vars returns names of top-level variables used by the template.
+func (t *Template) Vars() []string {
+	set := map[string]struct{}{}
+	walk := func(tmpl *template.Template) {
+		if tmpl != nil && tmpl.Tree != nil {
+			templateVars(tmpl.Tree.Root, set, false)
+		}
+	}
+
+	walk(t.task)
+	for _, b := range t.blocks {
+		walk(b.note)
+		for _, x := range b.text {
+			walk(x)
+		}
+	}
+
+	seq := make([]string, 0, len(set))
+	for k := range set {
+		seq = append(seq, k)
+	}
+	sort.Strings(seq)
+	return seq
+}
+
+// collects top-level variables, the dot is rebound by range and with blocks,
+// only fields rooted at $ refer to top-level variables inside of them.
+func templateVars(node parse.Node, set map[string]struct{}, nested bool) {
+	switch v := node.(type) {
+	case *parse.ListNode:
+		if v != nil {
+			for _, n := range v.Nodes {
+				templateVars(n, set, nested)
+			}
+		}
+	case *parse.ActionNode:
+		templateVars(v.Pipe, set, nested)
+	case *parse.PipeNode:
+		if v != nil {
+			for _, c := range v.Cmds {
+				templateVars(c, set, nested)
+			}
+		}
+	case *parse.CommandNode:
+		for _, a := range v.Args {
+			templateVars(a, set, nested)
+		}
+	case *parse.FieldNode:
+		if !nested {
+			set[v.Ident[0]] = struct{}{}
+		}
+	case *parse.VariableNode:
+		if v.Ident[0] == "$" && len(v.Ident) > 1 {
+			set[v.Ident[1]] = struct{}{}
+		}
+	case *parse.IfNode:
+		templateVars(v.Pipe, set, nested)
+		templateVars(v.List, set, nested)
+		templateVars(v.ElseList, set, nested)
+	case *parse.RangeNode:
+		templateVars(v.Pipe, set, nested)
+		templateVars(v.List, set, true)
+		templateVars(v.ElseList, set, nested)
+	case *parse.WithNode:
+		templateVars(v.Pipe, set, nested)
+		templateVars(v.List, set, true)
+		templateVars(v.ElseList, set, nested)
+	}
+}
+
+// Render the prompt with values of variables, either map or struct.
+func (t *Template) Render(vars any) (*Prompt, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	prompt := &Prompt{}
+
+	if t.task != nil {
+		task, err := render(t.task, vars)
+		if err != nil {
+			return nil, err
+		}
+		prompt.Task = Task(Sentence(task))
+	}
+
+	for _, b := range t.blocks {
+		note, err := render(b.note, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		text := make([]string, len(b.text))
+		for i, x := range b.text {
+			if text[i], err = render(x, vars); err != nil {
+				return nil, err
+			}
+		}
+
+		switch b.kind {
+		case blockGuide:
+			prompt.WithGuide(note, text...)
+		case blockRules:
+			prompt.WithRules(note, text...)
+		case blockFeedback:
+			prompt.WithFeedback(note, text...)
+		case blockExample:
+			prompt.WithExample(note, text[0])
+		case blockContext:
+			prompt.WithContext(note, text...)
+		case blockInput:
+			prompt.WithInput(note, text...)
+		case blockBlob:
+			prompt.WithBlob(note, text[0])
+		}
+	}
+
+	return prompt, nil
+}
+
+func render(tmpl *template.Template, vars any) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+	return sb.String(), nil
+}
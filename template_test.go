@@ -0,0 +1,107 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+func TestTemplate(t *testing.T) {
+	tmpl := NewTemplate().
+		WithTask("Translate the text to {{.Lang}}").
+		WithRules("Strictly adhere to the following requirements",
+			"Use {{.Tone}} tone",
+			"Keep 100% of the meaning",
+		).
+		WithExample("{{.Lang}}: Hello", "Hei").
+		WithInput("Text to translate", "{{.Text}}")
+
+	t.Run("Vars", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Nil(tmpl.Err()),
+			it.Seq(tmpl.Vars()).Equal("Lang", "Text", "Tone"),
+		)
+	})
+
+	t.Run("VarsNested", func(t *testing.T) {
+		tmpl := NewTemplate().
+			WithTask("Translate the text to {{$.Lang}}").
+			WithInput("Text to translate",
+				"{{range .Items}}{{.Text}} in {{$.Tone}} tone{{else}}{{.Empty}}{{end}}",
+				"{{with .Doc}}{{.Title}}{{end}}",
+			)
+
+		it.Then(t).Should(
+			it.Nil(tmpl.Err()),
+			it.Seq(tmpl.Vars()).Equal("Doc", "Empty", "Items", "Lang", "Tone"),
+		)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		prompt, err := tmpl.Render(map[string]any{
+			"Lang": "Finnish", "Tone": "formal", "Text": "Hello World",
+		})
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(prompt.Task), "Translate the text to Finnish."),
+			it.Seq(prompt.Content).Equal(
+				Rules{
+					Note: "Strictly adhere to the following requirements:",
+					Text: []string{"Use formal tone.", "Keep 100% of the meaning."},
+				},
+				Example{Input: "Finnish: Hello", Reply: "Hei"},
+				Input{Note: "Text to translate:", Text: []string{"Hello World"}},
+			),
+		)
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		type vars struct{ Lang, Tone, Text string }
+
+		prompt, err := tmpl.Render(vars{Lang: "Finnish", Tone: "formal", Text: "50%"})
+
+		it.Then(t).Should(
+			it.Nil(err),
+			it.Equal(string(prompt.Task), "Translate the text to Finnish."),
+			it.Seq(prompt.Content[2].(Input).Text).Equal("50%"),
+		)
+	})
+
+	t.Run("MissingMap", func(t *testing.T) {
+		_, err := tmpl.Render(map[string]any{"Lang": "Finnish", "Tone": "formal"})
+
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("Text"),
+		)
+	})
+
+	t.Run("MissingStruct", func(t *testing.T) {
+		type vars struct{ Lang, Tone string }
+
+		_, err := tmpl.Render(vars{Lang: "Finnish", Tone: "formal"})
+
+		it.Then(t).Should(
+			it.Error(struct{}{}, err).Contain("Text"),
+		)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tmpl := NewTemplate().WithTask("Translate {{.Lang")
+
+		_, err := tmpl.Render(nil)
+
+		it.Then(t).ShouldNot(
+			it.Nil(tmpl.Err()),
+			it.Nil(err),
+		)
+	})
+}
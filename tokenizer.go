@@ -0,0 +1,79 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+// Tokenizer estimates size of the text in tokens, as seen by the model.
+// It allows to check the context limits and to plan the usage before
+// prompting LLMs. See github.com/kshard/chatter/tokenizer for implementations.
+type Tokenizer interface {
+	CountTokens(string) int
+}
+
+// Number of tokens used by chat markup to frame each message
+// (e.g. role and message separators).
+const TokensPerMessage = 4
+
+// CountTokens estimates the size of the conversation in tokens.
+// The conversation is counted as its textual representation seen by LLMs:
+// [Stratum], [Text], [Prompt], [Reply] and [Answer] are supported,
+// other messages are counted using its string representation.
+func CountTokens(tokenizer Tokenizer, seq []Message) int {
+	n := 0
+	for _, msg := range seq {
+		if _, ok := msg.(CachePoint); ok {
+			// checkpoint is not a part of the prompt
+			continue
+		}
+		n += TokensPerMessage + countMessage(tokenizer, msg)
+	}
+	return n
+}
+
+func countMessage(tokenizer Tokenizer, msg Message) int {
+	switch v := msg.(type) {
+	case Stratum:
+		return tokenizer.CountTokens(string(v))
+	case Text:
+		return tokenizer.CountTokens(string(v))
+	case *Prompt:
+		return tokenizer.CountTokens(v.String())
+	case *Reply:
+		n := 0
+		for _, c := range v.Content {
+			n += countContent(tokenizer, c)
+		}
+		return n
+	case *Answer:
+		n := 0
+		for _, y := range v.Yield {
+			n += countContent(tokenizer, y)
+		}
+		return n
+	default:
+		return tokenizer.CountTokens(msg.String())
+	}
+}
+
+func countContent(tokenizer Tokenizer, c Content) int {
+	switch v := c.(type) {
+	case Text:
+		return tokenizer.CountTokens(string(v))
+	case Json:
+		return tokenizer.CountTokens(v.Source) + tokenizer.CountTokens(string(v.Value))
+	case Invoke:
+		return tokenizer.CountTokens(v.Cmd) + tokenizer.CountTokens(string(v.Args.Value))
+	case Reasoning:
+		return tokenizer.CountTokens(v.Text)
+	case Vector, Binary, *Binary:
+		// non textual content is not counted
+		return 0
+	default:
+		return tokenizer.CountTokens(c.String())
+	}
+}
@@ -0,0 +1,203 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kshard/chatter"
+)
+
+// Pre-tokenization patterns of OpenAI vocabularies. The original patterns use
+// negative lookahead `\s+(?!\S)`, which is not supported by RE2. It is replaced
+// with `\s+` and emulated by the splitter.
+var patterns = map[string]*regexp.Regexp{
+	CL100K: regexp.MustCompile(`^(?:` +
+		`(?i:'s|'t|'re|'ve|'m|'ll|'d)` +
+		`|[^\r\n\p{L}\p{N}]?\p{L}+` +
+		`|\p{N}{1,3}` +
+		`| ?[^\s\p{L}\p{N}]+[\r\n]*` +
+		`|\s*[\r\n]+` +
+		`|\s+)`),
+
+	O200K: regexp.MustCompile(`^(?:` +
+		`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}` +
+		`| ?[^\s\p{L}\p{N}]+[\r\n/]*` +
+		`|\s*[\r\n]+` +
+		`|\s+)`),
+}
+
+// BPE is byte-pair encoding tokenizer compatible with OpenAI tiktoken.
+type BPE struct {
+	ranks   map[string]int
+	pattern *regexp.Regexp
+}
+
+var _ chatter.Tokenizer = (*BPE)(nil)
+
+// Create BPE tokenizer for the vocabulary (cl100k_base or o200k_base).
+// The vocabulary is read in tiktoken format, each line is base64 encoded
+// token followed by its rank. The files are distributed by OpenAI, use
+// go:embed to bundle them with the application:
+//
+//	//go:embed o200k_base.tiktoken
+//	var o200k []byte
+//
+//	bpe, err := tokenizer.NewBPE(tokenizer.O200K, bytes.NewReader(o200k))
+func NewBPE(encoding string, vocab io.Reader) (*BPE, error) {
+	pattern, has := patterns[encoding]
+	if !has {
+		return nil, fmt.Errorf("unknown encoding %s", encoding)
+	}
+
+	ranks, err := readRanks(vocab)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BPE{ranks: ranks, pattern: pattern}, nil
+}
+
+func readRanks(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		token, rank, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid vocabulary entry: %s", line)
+		}
+
+		b, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vocabulary token %s: %w", token, err)
+		}
+
+		n, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vocabulary rank %s: %w", rank, err)
+		}
+
+		ranks[string(b)] = n
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ranks, nil
+}
+
+// Encode text into sequence of tokens.
+func (bpe *BPE) Encode(text string) []int {
+	seq := make([]int, 0)
+	bpe.split(text, func(piece string) {
+		if rank, has := bpe.ranks[piece]; has {
+			seq = append(seq, rank)
+			return
+		}
+
+		parts := bpe.merge(piece)
+		for i := 0; i < len(parts)-1; i++ {
+			rank, has := bpe.ranks[piece[parts[i]:parts[i+1]]]
+			if !has {
+				rank = -1
+			}
+			seq = append(seq, rank)
+		}
+	})
+
+	return seq
+}
+
+// CountTokens returns number of tokens in the text.
+func (bpe *BPE) CountTokens(text string) int {
+	n := 0
+	bpe.split(text, func(piece string) {
+		if _, has := bpe.ranks[piece]; has {
+			n++
+			return
+		}
+		n += len(bpe.merge(piece)) - 1
+	})
+
+	return n
+}
+
+// split text into pieces using pre-tokenization pattern
+func (bpe *BPE) split(text string, f func(string)) {
+	for len(text) > 0 {
+		end := len(bpe.pattern.FindString(text))
+		if end == 0 {
+			_, end = utf8.DecodeRuneInString(text)
+		}
+
+		// emulates `\s+(?!\S)`, the last whitespace is left for the next piece
+		if end < len(text) && isBlank(text[:end]) && !strings.HasSuffix(text[:end], "\n") && !strings.HasSuffix(text[:end], "\r") {
+			if _, n := utf8.DecodeLastRuneInString(text[:end]); n < end {
+				end -= n
+			}
+		}
+
+		f(text[:end])
+		text = text[end:]
+	}
+}
+
+// merge bytes of the piece using ranks, returns boundaries of tokens
+func (bpe *BPE) merge(piece string) []int {
+	parts := make([]int, len(piece)+1)
+	for i := range parts {
+		parts[i] = i
+	}
+
+	for len(parts) > 2 {
+		minRank, minAt := math.MaxInt, -1
+		for i := 0; i < len(parts)-2; i++ {
+			if rank, has := bpe.ranks[piece[parts[i]:parts[i+2]]]; has && rank < minRank {
+				minRank, minAt = rank, i
+			}
+		}
+
+		if minAt == -1 {
+			break
+		}
+
+		parts = append(parts[:minAt+1], parts[minAt+2:]...)
+	}
+
+	return parts
+}
+
+// whitespace as defined by RE2 `\s`
+func isBlank(s string) bool {
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '\f', '\r':
+		default:
+			return false
+		}
+	}
+	return true
+}
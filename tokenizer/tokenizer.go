@@ -0,0 +1,82 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+// Package tokenizer implements offline, pure Go token counting for LLMs.
+//
+// OpenAI models are covered by byte-pair encoding (BPE) using the vocabularies
+// cl100k_base and o200k_base. Other vendors (AWS Bedrock, Google Gemini) do not
+// publish tokenizers, their token counts are estimated by heuristic calibrated
+// per model family.
+//
+// The vocabularies are not bundled with the package, they are published by
+// OpenAI in tiktoken format (about 1.7 MB and 3.6 MB):
+//
+//	https://openaipublic.blob.core.windows.net/encodings/cl100k_base.tiktoken
+//	https://openaipublic.blob.core.windows.net/encodings/o200k_base.tiktoken
+//
+// Applications that need exact counts download the files and load them with
+// [NewBPE], otherwise token counts are estimated by [ForModel] heuristic.
+package tokenizer
+
+import (
+	"strings"
+
+	"github.com/kshard/chatter"
+)
+
+// Name of BPE vocabularies
+const (
+	CL100K = "cl100k_base"
+	O200K  = "o200k_base"
+)
+
+// Encoding returns the name of the BPE vocabulary used by the OpenAI model,
+// empty string is returned if the model is not known.
+func Encoding(model string) string {
+	m := strings.ToLower(model)
+	switch {
+	case strings.HasPrefix(m, "gpt-4o"),
+		strings.HasPrefix(m, "gpt-4.1"),
+		strings.HasPrefix(m, "gpt-4.5"),
+		strings.HasPrefix(m, "gpt-5"),
+		strings.HasPrefix(m, "o1"),
+		strings.HasPrefix(m, "o3"),
+		strings.HasPrefix(m, "o4"),
+		strings.HasPrefix(m, "chatgpt-4o"):
+		return O200K
+	case strings.HasPrefix(m, "gpt-4"),
+		strings.HasPrefix(m, "gpt-3.5"),
+		strings.HasPrefix(m, "gpt-35"),
+		strings.HasPrefix(m, "text-embedding-3"),
+		strings.HasPrefix(m, "text-embedding-ada-002"):
+		return CL100K
+	default:
+		return ""
+	}
+}
+
+// ForModel returns heuristic tokenizer calibrated for the model family,
+// the generic heuristic is returned for unknown models (including OpenAI).
+// Use [NewBPE] for exact token counts of OpenAI models.
+func ForModel(model string) chatter.Tokenizer {
+	m := strings.ToLower(model)
+	switch {
+	case strings.Contains(m, "claude"), strings.Contains(m, "anthropic"):
+		return heuristicClaude
+	case strings.Contains(m, "gemini"), strings.Contains(m, "gemma"):
+		return heuristicGemini
+	case strings.Contains(m, "nova"), strings.Contains(m, "titan"):
+		return heuristicNova
+	case strings.Contains(m, "llama"):
+		return heuristicLlama
+	case strings.Contains(m, "mistral"), strings.Contains(m, "mixtral"):
+		return heuristicMistral
+	default:
+		return heuristicDefault
+	}
+}
@@ -0,0 +1,107 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package tokenizer_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+	"github.com/kshard/chatter"
+	"github.com/kshard/chatter/tokenizer"
+)
+
+// vocabulary with all bytes and few merges of "hello"
+func vocab() string {
+	var sb strings.Builder
+	for i := range 256 {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), i)
+	}
+	for i, t := range []string{"he", "ll", "hell", "hello", " w", "or", " wor", "ld"} {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(t)), 256+i)
+	}
+	return sb.String()
+}
+
+func TestBPE(t *testing.T) {
+	bpe, err := tokenizer.NewBPE(tokenizer.CL100K, strings.NewReader(vocab()))
+	it.Then(t).Should(it.Nil(err))
+
+	t.Run("Merge", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(bpe.Encode("hello")).Equal(259),
+			it.Seq(bpe.Encode("hello world")).Equal(259, 262, 263),
+			it.Equal(bpe.CountTokens("hello world"), 3),
+		)
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		it.Then(t).Should(
+			it.Seq(bpe.Encode("hi")).Equal(int('h'), int('i')),
+			it.Equal(bpe.CountTokens("привет"), 12),
+		)
+	})
+
+	t.Run("Whitespace", func(t *testing.T) {
+		// "a", "  ", " b": the last space is joined with the next word
+		it.Then(t).Should(
+			it.Equal(bpe.CountTokens("a   b"), 5),
+			it.Equal(bpe.CountTokens("a   "), 4),
+		)
+	})
+
+	t.Run("Contractions", func(t *testing.T) {
+		// "I", "'m", " ", "123", "45"
+		it.Then(t).Should(
+			it.Equal(bpe.CountTokens("I'm 12345"), 9),
+		)
+	})
+}
+
+func TestBPEUnknownEncoding(t *testing.T) {
+	_, err := tokenizer.NewBPE("p50k_base", strings.NewReader(vocab()))
+	it.Then(t).ShouldNot(it.Nil(err))
+}
+
+func TestBPEInvalidVocabulary(t *testing.T) {
+	_, err := tokenizer.NewBPE(tokenizer.O200K, strings.NewReader("aGVsbG8="))
+	it.Then(t).ShouldNot(it.Nil(err))
+}
+
+func TestHeuristic(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal(tokenizer.ForModel("").CountTokens(""), 0),
+		it.Equal(tokenizer.ForModel("").CountTokens("The quick brown fox jumps over the lazy dog."), 11),
+		it.Equal(tokenizer.ForModel("claude").CountTokens("The quick brown fox jumps over the lazy dog."), 12),
+		it.Equal(tokenizer.ForModel("").CountTokens("你好世界"), 4),
+		it.Equal(tokenizer.ForModel("").CountTokens(`{"a": 1}`), 7),
+	)
+}
+
+func TestForModel(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal[chatter.Tokenizer](tokenizer.ForModel("us.anthropic.claude-3-7-sonnet-20250219-v1:0"), tokenizer.Heuristic{CharsPerToken: 3.5, TokensPerIdeograph: 1.3}),
+		it.Equal[chatter.Tokenizer](tokenizer.ForModel("gemini-1.5-pro"), tokenizer.Heuristic{CharsPerToken: 4.0, TokensPerIdeograph: 0.9}),
+		it.Equal[chatter.Tokenizer](tokenizer.ForModel("amazon.nova-pro-v1:0"), tokenizer.Heuristic{CharsPerToken: 3.8, TokensPerIdeograph: 1.0}),
+		it.Equal[chatter.Tokenizer](tokenizer.ForModel("meta.llama3-1-70b-instruct-v1:0"), tokenizer.Heuristic{CharsPerToken: 4.0, TokensPerIdeograph: 1.0}),
+		it.Equal[chatter.Tokenizer](tokenizer.ForModel("gpt-4o"), tokenizer.Heuristic{CharsPerToken: 4.0, TokensPerIdeograph: 1.0}),
+	)
+}
+
+func TestEncoding(t *testing.T) {
+	it.Then(t).Should(
+		it.Equal(tokenizer.Encoding("gpt-4o-mini"), tokenizer.O200K),
+		it.Equal(tokenizer.Encoding("o3-mini"), tokenizer.O200K),
+		it.Equal(tokenizer.Encoding("gpt-4-turbo"), tokenizer.CL100K),
+		it.Equal(tokenizer.Encoding("text-embedding-3-small"), tokenizer.CL100K),
+		it.Equal(tokenizer.Encoding("claude"), ""),
+	)
+}
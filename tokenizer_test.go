@@ -0,0 +1,44 @@
+//
+// Copyright (C) 2025 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/kshard/chatter
+//
+
+package chatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+// counts words as tokens
+type words struct{}
+
+func (words) CountTokens(s string) int { return len(strings.Fields(s)) }
+
+func TestCountTokens(t *testing.T) {
+	prompt := &Prompt{}
+	prompt.WithTask("Translate the text")
+
+	seq := []Message{
+		Stratum("act as translator"),
+		prompt,
+		&Reply{Content: []Content{
+			Text("let me call the tool"),
+			Invoke{Cmd: "translate", Args: Json{Value: []byte(`{"text": "hello"}`)}},
+			Vector{1.0, 2.0},
+		}},
+		&Answer{Yield: []Json{{Source: "translate", Value: []byte(`{"text": "hola"}`)}}},
+		Text("thank you"),
+	}
+
+	it.Then(t).Should(
+		it.Equal(CountTokens(words{}, nil), 0),
+		it.Equal(CountTokens(words{}, seq[:1]), TokensPerMessage+3),
+		it.Equal(CountTokens(words{}, seq), 5*TokensPerMessage+3+3+(5+1+2)+(1+2)+2),
+	)
+}